@@ -1 +1 @@
-go run src\main.go src\distance.go src\loader.go src\optimizer.go src\schaakbond.go data\SchemaIndeling.xlsx data\Indeling.xlsx data\distance.cache na
+go run src\main.go src\afwisseling.go src\checkpoint.go src\cluster.go src\columns.go src\cost.go src\distance.go src\export.go src\formats.go src\herhaling.go src\herstel.go src\geojson.go src\indeling.go src\kalender.go src\loader.go src\objective.go src\optimizer.go src\pareto.go src\pins.go src\programma.go src\report.go src\schaakbond.go src\stopping.go src\validate.go src\verhinderd.go data\SchemaIndeling.xlsx data\Indeling.xlsx data\distance.cache na
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+//Afwisseling of thuis and uit of the teams of a vereniging over the
+//klasses, so they don't play thuis in the same ronde and can share boards
+//and clocks. Every pair of teams playing thuis in the same ronde multiplies
+//the cost by the penalty
+type Afwisseling struct {
+	verenigingen []string
+	teams        map[string][]TeamCostID
+	penalty      float64
+}
+
+//ThuisConflict of a vereniging: a ronde in which several of its teams play
+//thuis
+type ThuisConflict struct {
+	Vereniging string   `json:"vereniging"`
+	Ronde      int      `json:"ronde"`
+	Teams      []string `json:"teams"`
+}
+
+//NewAfwisseling for the verenigingen given by id, * for every vereniging with
+//more than one team
+func (optimizer *Optimizer) NewAfwisseling(ids []string, penalty float64) (*Afwisseling, error) {
+	afwisseling := new(Afwisseling)
+	afwisseling.teams = make(map[string][]TeamCostID)
+	afwisseling.penalty = penalty
+
+	if len(ids) == 1 && ids[0] == "*" {
+		ids = nil
+		for id, v := range optimizer.bond.verenigingen {
+			if len(v.teams) > 1 {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		v, ok := optimizer.bond.verenigingen[id]
+
+		if !ok {
+			return nil, fmt.Errorf("Unknown vereniging %v for afwisseling", id)
+		}
+
+		teamIDs := make([]string, 0, len(v.teams))
+		for teamID := range v.teams {
+			teamIDs = append(teamIDs, teamID)
+		}
+		sort.Strings(teamIDs)
+
+		teams := make([]TeamCostID, len(teamIDs))
+		for ix, teamID := range teamIDs {
+			teams[ix] = optimizer.matrix.GetTeamCostID(teamID)
+		}
+
+		afwisseling.verenigingen = append(afwisseling.verenigingen, id)
+		afwisseling.teams[id] = teams
+	}
+
+	return afwisseling, nil
+}
+
+//thuis of the teams in a ronde, by TeamCostID
+func thuis(X Vector, ronde int) [256]bool {
+	var result [256]bool
+
+	for ix, tid := range X {
+		if description := optimizer.descriptions[ix]; description != nil {
+			result[tid] = optimizer.schema.Loten[ix-description.begin].Rondes[ronde].Verplaatsing == Thuis
+		}
+	}
+
+	return result
+}
+
+//Conflicten of the vector, a conflict per vereniging and ronde
+func (afwisseling *Afwisseling) Conflicten(X Vector) []ThuisConflict {
+	var conflicten []ThuisConflict
+
+	for ronde := range optimizer.schema.Rondes {
+		thuisTeams := thuis(X, ronde)
+
+		for _, id := range afwisseling.verenigingen {
+			var teams []string
+			for _, tid := range afwisseling.teams[id] {
+				if thuisTeams[tid] {
+					teams = append(teams, optimizer.matrix.GetTeamInfoByCostID(tid).team.id)
+				}
+			}
+
+			if len(teams) > 1 {
+				conflicten = append(conflicten, ThuisConflict{id, ronde + 1, teams})
+			}
+		}
+	}
+
+	return conflicten
+}
+
+//multiplier of the cost of the vector, the penalty for every pair of teams
+//of a vereniging playing thuis in the same ronde
+func (afwisseling *Afwisseling) multiplier(X Vector) float64 {
+	pairs := 0
+
+	for ronde := range optimizer.schema.Rondes {
+		thuisTeams := thuis(X, ronde)
+
+		for _, id := range afwisseling.verenigingen {
+			count := 0
+			for _, tid := range afwisseling.teams[id] {
+				if thuisTeams[tid] {
+					count++
+				}
+			}
+			pairs += count * (count - 1) / 2
+		}
+	}
+
+	return math.Pow(afwisseling.penalty, float64(pairs))
+}
+
+//String of a conflict
+func (conflict ThuisConflict) String() string {
+	return fmt.Sprintf("vereniging %s ronde %d: %s thuis", conflict.Vereniging, conflict.Ronde, strings.Join(conflict.Teams, ", "))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+//thuisRondes of a lot in the test schema, loten and rondes count from 1
+func thuisRondes(lot int) map[int]bool {
+	rondes := make(map[int]bool)
+
+	for _, w := range testSchemaWedstrijden() {
+		if w.Thuis == lot {
+			rondes[w.Ronde] = true
+		}
+	}
+
+	return rondes
+}
+
+//vectorMetLoten of the test vector with teams moved to a position
+func vectorMetLoten(t *testing.T, posities map[string]int) Vector {
+	X := testVector(t)
+
+	for teamID, position := range posities {
+		q := positionOf(X, optimizer.matrix.GetTeamCostID(teamID))
+		X[q], X[position] = X[position], X[q]
+	}
+
+	return X
+}
+
+func TestAfwisseling(t *testing.T) {
+	tests := []struct {
+		naam string
+		//lot in M of 0100011 and in 1A of 0100012, of vereniging 010001
+		lotM, lot1 int
+	}{
+		{"same lot", 1, 1},
+		{"fixed lot", 10, 10},
+		{"other lot", 1, 2},
+		{"opposite of the fixed lot", 10, 1},
+	}
+
+	for _, test := range tests {
+		newTestOptimizer(t)
+		X := vectorMetLoten(t, map[string]int{"0100011": test.lotM - 1, "0100012": 10 + test.lot1 - 1})
+
+		afwisseling, err := optimizer.NewAfwisseling([]string{"010001"}, 2)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pairs := 0
+		thuisM := thuisRondes(test.lotM)
+		for ronde := range thuisRondes(test.lot1) {
+			if thuisM[ronde] {
+				pairs++
+			}
+		}
+
+		if m := afwisseling.multiplier(X); m != math.Pow(2, float64(pairs)) {
+			t.Errorf("%v: multiplier %v, expected 2^%d", test.naam, m, pairs)
+		}
+
+		if conflicten := afwisseling.Conflicten(X); len(conflicten) != pairs {
+			t.Errorf("%v: conflicten %v, expected %d", test.naam, conflicten, pairs)
+		}
+	}
+}
+
+func TestNewAfwisseling(t *testing.T) {
+	newTestOptimizer(t)
+
+	tests := []struct {
+		naam         string
+		ids          []string
+		verenigingen int
+		fout         bool
+	}{
+		{"one", []string{"010001"}, 1, false},
+		{"every vereniging with more teams", []string{"*"}, 15, false},
+		{"unknown", []string{"019999"}, 0, true},
+	}
+
+	for _, test := range tests {
+		afwisseling, err := optimizer.NewAfwisseling(test.ids, 2)
+
+		if (err != nil) != test.fout {
+			t.Errorf("%v: error %v", test.naam, err)
+			continue
+		}
+
+		if err == nil && len(afwisseling.verenigingen) != test.verenigingen {
+			t.Errorf("%v: %d verenigingen, expected %d", test.naam, len(afwisseling.verenigingen), test.verenigingen)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MaxHalford/gago"
+)
+
+//Checkpoint of an optimization run, genomes are stored as team ids so they
+//stay valid when the TeamCostIDs are handed out differently
+type Checkpoint struct {
+	Seed        int64
+	Generation  int
+	Fitness     float64
+	Best        []string
+	Populations [][][]string
+	StopReason  string  `json:",omitempty"`
+	Baseline    float64 `json:",omitempty"`
+	//Parameters of the run, see RunParameters
+	Parameters map[string]string `json:",omitempty"`
+}
+
+//runParameters of the current run, stored in every checkpoint
+var runParameters map[string]string
+
+//RunParameters of a run: a sha256 of the content of every input file by
+//naam, empty file names are left out, and the settings by naam. A run can
+//only be resumed with the same parameters
+func RunParameters(files map[string]string, settings map[string]string) (map[string]string, error) {
+	parameters := make(map[string]string)
+
+	for naam, fileName := range files {
+		if fileName == "" {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(fileName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		parameters[naam] = fmt.Sprintf("%x", sha256.Sum256(content))
+	}
+
+	for naam, value := range settings {
+		parameters[naam] = value
+	}
+
+	return parameters, nil
+}
+
+//CheckParameters of the checkpoint against those of the run resuming it
+func (checkpoint *Checkpoint) CheckParameters(parameters map[string]string) error {
+	namen := make(map[string]bool)
+	for naam := range checkpoint.Parameters {
+		namen[naam] = true
+	}
+	for naam := range parameters {
+		namen[naam] = true
+	}
+
+	var verschillen []string
+	for naam := range namen {
+		if checkpoint.Parameters[naam] != parameters[naam] {
+			verschillen = append(verschillen, fmt.Sprintf("%v (%q instead of %q)", naam, parameters[naam], checkpoint.Parameters[naam]))
+		}
+	}
+
+	if len(verschillen) > 0 {
+		sort.Strings(verschillen)
+		return fmt.Errorf("Checkpoint is of a different run, changed: %v", strings.Join(verschillen, ", "))
+	}
+
+	return nil
+}
+
+//TranslateToTeamIDs translate a vector to team ids
+func (X Vector) TranslateToTeamIDs() []string {
+	result := make([]string, len(X), len(X))
+
+	for ix, tid := range X {
+		result[ix] = optimizer.matrix.GetTeamInfoByCostID(tid).team.id
+	}
+
+	return result
+}
+
+//VectorFromTeamIDs translate team ids to a vector, every team must be on a
+//position of its own klasse
+func (optimizer *Optimizer) VectorFromTeamIDs(teamIDs []string) (Vector, error) {
+	if len(teamIDs) != len(optimizer.descriptions) {
+		return nil, fmt.Errorf("Division has %d teams, expected %d", len(teamIDs), len(optimizer.descriptions))
+	}
+
+	infos, err := optimizer.matrix.TranslateToTeamInfos(teamIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vector := make([]TeamCostID, len(infos), len(infos))
+	seen := make(map[TeamCostID]bool)
+
+	for ix, info := range infos {
+		if optimizer.descriptions[ix] == nil {
+			return nil, fmt.Errorf("Position %d of team %v is not part of a group", ix, info.team.id)
+		}
+
+		if info.team.klasse != optimizer.descriptions[ix].klasseGroup.klasse {
+			return nil, fmt.Errorf("Team %v of klasse %v on position %d of klasse %v", info.team.id, info.team.klasse, ix, optimizer.descriptions[ix].klasseGroup.klasse)
+		}
+
+		if seen[info.teamCostID] {
+			return nil, fmt.Errorf("Team %v appears more than once", info.team.id)
+		}
+
+		seen[info.teamCostID] = true
+		vector[ix] = info.teamCostID
+	}
+
+	return Vector(vector), nil
+}
+
+//NewCheckpoint of the current state of the genetic algorithm
+func NewCheckpoint(ga *gago.GA, generation int, seed int64) *Checkpoint {
+	checkpoint := new(Checkpoint)
+	checkpoint.Seed = seed
+	checkpoint.Generation = generation
+	checkpoint.Fitness = ga.Best.Fitness
+	checkpoint.Parameters = runParameters
+	checkpoint.Best = ga.Best.Genome.(Vector).TranslateToTeamIDs()
+	checkpoint.Populations = make([][][]string, len(ga.Populations), len(ga.Populations))
+
+	for p, pop := range ga.Populations {
+		checkpoint.Populations[p] = make([][]string, len(pop.Individuals), len(pop.Individuals))
+
+		for i, indi := range pop.Individuals {
+			checkpoint.Populations[p][i] = indi.Genome.(Vector).TranslateToTeamIDs()
+		}
+	}
+
+	return checkpoint
+}
+
+//LoadCheckpoint from a json file
+func LoadCheckpoint(fileName string) (*Checkpoint, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := new(Checkpoint)
+	err = json.Unmarshal(file, checkpoint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+//Save checkpoint to a json file, the previous checkpoint is only replaced
+//once the new one is completely written
+func (checkpoint *Checkpoint) Save(fileName string) error {
+	b, err := json.Marshal(checkpoint)
+
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(fileName+".tmp", b, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(fileName+".tmp", fileName)
+}
+
+//Restore the populations of the genetic algorithm, when the sizes differ the
+//stored genomes are repeated or truncated
+func (checkpoint *Checkpoint) Restore(ga *gago.GA) error {
+	if len(checkpoint.Populations) == 0 {
+		return fmt.Errorf("Checkpoint contains no populations")
+	}
+
+	for p, stored := range checkpoint.Populations {
+		if len(stored) == 0 {
+			return fmt.Errorf("Population %d of the checkpoint contains no divisions", p+1)
+		}
+	}
+
+	for p := range ga.Populations {
+		stored := checkpoint.Populations[p%len(checkpoint.Populations)]
+
+		for i := range ga.Populations[p].Individuals {
+			vector, err := optimizer.VectorFromTeamIDs(stored[i%len(stored)])
+
+			if err != nil {
+				return err
+			}
+
+			optimizer.Repair(vector, ga.RNG)
+			ga.Populations[p].Individuals[i].Genome = vector
+			ga.Populations[p].Individuals[i].Evaluate()
+		}
+	}
+
+	return checkpoint.InsertBest(ga)
+}
+
+//InsertBest division of the checkpoint into the populations of the genetic
+//algorithm
+func (checkpoint *Checkpoint) InsertBest(ga *gago.GA) error {
+	vector, err := optimizer.VectorFromTeamIDs(checkpoint.Best)
+
+	if err != nil {
+		return err
+	}
+
+	InsertVector(ga, vector)
+
+	return nil
+}
+
+//InsertVector into the populations of the genetic algorithm, replacing the
+//first individual of every population
+func InsertVector(ga *gago.GA, vector Vector) {
+	for p := range ga.Populations {
+		genome := make(Vector, len(vector), len(vector))
+		copy(genome, vector)
+		optimizer.Repair(genome, ga.RNG)
+
+		ga.Populations[p].Individuals[0].Genome = genome
+		ga.Populations[p].Individuals[0].Evaluate()
+	}
+
+	if len(ga.Populations) > 0 && ga.Populations[0].Individuals[0].Fitness < ga.Best.Fitness {
+		ga.Best = ga.Populations[0].Individuals[0]
+	}
+}
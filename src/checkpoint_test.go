@@ -0,0 +1,182 @@
+package main
+
+import (
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MaxHalford/gago"
+)
+
+//testGA with populations of the given sizes, every individual the vector
+func testGA(X Vector, sizes ...int) *gago.GA {
+	ga := new(gago.GA)
+	ga.RNG = rand.New(rand.NewSource(1))
+	ga.Best.Fitness = math.Inf(1)
+
+	for _, size := range sizes {
+		var population gago.Population
+		population.Individuals = make(gago.Individuals, size)
+
+		for i := range population.Individuals {
+			genome := make(Vector, len(X))
+			copy(genome, X)
+			population.Individuals[i].Genome = genome
+			population.Individuals[i].Evaluate()
+		}
+
+		ga.Populations = append(ga.Populations, population)
+	}
+
+	ga.Best = ga.Populations[0].Individuals[0]
+	return ga
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+	X[0], X[1] = X[1], X[0]
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	runParameters = map[string]string{"init": "random"}
+	defer func() { runParameters = nil }()
+
+	fileName := filepath.Join(dir, "checkpoint.json")
+	checkpoint := NewCheckpoint(testGA(X, 2, 3), 7, 42)
+
+	if err = checkpoint.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCheckpoint(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded, checkpoint) {
+		t.Errorf("loaded %+v, saved %+v", loaded, checkpoint)
+	}
+
+	if _, err = os.Stat(fileName + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestCheckpointRestore(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+	teamIDs := X.TranslateToTeamIDs()
+
+	swapped := make(Vector, len(X))
+	copy(swapped, X)
+	swapped[10], swapped[20] = swapped[20], swapped[10]
+
+	unknown := append([]string{"9999999"}, teamIDs[1:]...)
+
+	tests := []struct {
+		naam        string
+		populations [][][]string
+		sizes       []int
+		fout        bool
+	}{
+		{"no populations", [][][]string{}, []int{2}, true},
+		{"empty population", [][][]string{{teamIDs}, {}}, []int{2, 2}, true},
+		{"unknown team", [][][]string{{unknown}}, []int{2}, true},
+		{"repeated", [][][]string{{swapped.TranslateToTeamIDs()}}, []int{3, 2}, false},
+		{"truncated", [][][]string{{swapped.TranslateToTeamIDs(), teamIDs, teamIDs}}, []int{1}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			checkpoint := NewCheckpoint(testGA(X, 1), 1, 42)
+			checkpoint.Populations = test.populations
+
+			ga := testGA(X, test.sizes...)
+			err := checkpoint.Restore(ga)
+
+			if test.fout {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for p, population := range ga.Populations {
+				for i, indi := range population.Individuals {
+					//the first individual is replaced by the best division
+					if i == 0 {
+						continue
+					}
+
+					if !reflect.DeepEqual(indi.Genome.(Vector), swapped) {
+						t.Errorf("individual %d of population %d not restored", i, p)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCheckpointParameters(t *testing.T) {
+	teams, removeTeams := writeTestFile(t, "teams.csv", "teamid\n0100011\n")
+	defer removeTeams()
+	anders, removeAnders := writeTestFile(t, "anders.csv", "teamid\n0100012\n")
+	defer removeAnders()
+
+	parameters := func(teamsFileName string, settings map[string]string) map[string]string {
+		p, err := RunParameters(map[string]string{"teams": teamsFileName, "pins": ""}, settings)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return p
+	}
+
+	checkpoint := new(Checkpoint)
+	checkpoint.Parameters = parameters(teams, map[string]string{"init": "random"})
+
+	if _, ok := checkpoint.Parameters["pins"]; ok {
+		t.Error("Empty file name in the parameters")
+	}
+
+	tests := []struct {
+		naam       string
+		parameters map[string]string
+		fout       string
+	}{
+		{"same run", parameters(teams, map[string]string{"init": "random"}), ""},
+		{"other teams", parameters(anders, map[string]string{"init": "random"}), "teams"},
+		{"other setting", parameters(teams, map[string]string{"init": "cluster"}), "init"},
+		{"extra setting", parameters(teams, map[string]string{"init": "random", "repeat-km": "25"}), "repeat-km"},
+	}
+
+	for _, test := range tests {
+		err := checkpoint.CheckParameters(test.parameters)
+
+		if (err == nil) != (test.fout == "") || (err != nil && !strings.Contains(err.Error(), test.fout)) {
+			t.Errorf("%v: error %v, expected %q", test.naam, err, test.fout)
+		}
+	}
+
+	if _, err := RunParameters(map[string]string{"teams": teams + ".missing"}, nil); err == nil {
+		t.Error("No error of a missing input file")
+	}
+}
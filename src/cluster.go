@@ -0,0 +1,284 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/MaxHalford/gago"
+)
+
+//clusterIterations maximum of the k-medoids iterations
+const clusterIterations = 10
+
+//clusterSwaps maximum of the random swaps between groepen after clustering
+const clusterSwaps = 3
+
+//afstand between two teams in meters, 0 for the same team
+func afstand(a, b TeamCostID) float64 {
+	if a == b {
+		return 0
+	}
+
+	travelInfo := optimizer.matrix.GetTeamsTravelCost(a, b)
+
+	if travelInfo == nil {
+		return 0
+	}
+
+	return float64(travelInfo.Distance)
+}
+
+//kiesMedoids k-means++ style, the first at random and every next one with a
+//chance proportional to the squared distance to the nearest medoid so far
+func kiesMedoids(teams []TeamCostID, k int, rng *rand.Rand) []TeamCostID {
+	medoids := []TeamCostID{teams[rng.Intn(len(teams))]}
+
+	for len(medoids) < k {
+		weights := make([]float64, len(teams))
+		total := 0.0
+
+		for ix, t := range teams {
+			nearest := -1.0
+			for _, m := range medoids {
+				if d := afstand(t, m); nearest < 0 || d < nearest {
+					nearest = d
+				}
+			}
+			weights[ix] = nearest * nearest
+			total += weights[ix]
+		}
+
+		pick := rng.Float64() * total
+		chosen := teams[len(teams)-1]
+		for ix, w := range weights {
+			if pick < w {
+				chosen = teams[ix]
+				break
+			}
+			pick -= w
+		}
+
+		if teamInSlice(chosen, medoids) {
+			//all remaining teams are at a medoid, take any other
+			for _, t := range teams {
+				if !teamInSlice(t, medoids) {
+					chosen = t
+					break
+				}
+			}
+		}
+
+		medoids = append(medoids, chosen)
+	}
+
+	return medoids
+}
+
+//past team in a cluster without a team of its vereniging and within the P/D
+//quota of a groep, 2 promovendi or kampioenen and 1 degradant
+func past(cluster []TeamCostID, team TeamCostID) bool {
+	t := optimizer.matrix.GetTeamInfoByCostID(team).team
+	var promovendi, degradanten int
+
+	for _, c := range cluster {
+		ct := optimizer.matrix.GetTeamInfoByCostID(c).team
+
+		if ct.vereniging.id == t.vereniging.id {
+			return false
+		}
+
+		switch ct.pd {
+		case Promotie, Kampioen:
+			promovendi++
+		case Degradatie:
+			degradanten++
+		}
+	}
+
+	switch t.pd {
+	case Promotie, Kampioen:
+		return promovendi < 2
+	case Degradatie:
+		return degradanten < 1
+	}
+
+	return true
+}
+
+//wijsToe every team to a medoid with at most size teams per medoid, the
+//closest pairs first: at first only to a cluster the team past in, then the
+//teams left to the closest medoid with room. Teams left when every medoid is
+//full are returned separately
+func wijsToe(teams []TeamCostID, medoids []TeamCostID, size int) ([][]TeamCostID, []TeamCostID) {
+	type paar struct {
+		team, medoid int
+		afstand      float64
+	}
+
+	paren := make([]paar, 0, len(teams)*len(medoids))
+	for t := range teams {
+		for m := range medoids {
+			paren = append(paren, paar{t, m, afstand(teams[t], medoids[m])})
+		}
+	}
+	sort.SliceStable(paren, func(i, j int) bool { return paren[i].afstand < paren[j].afstand })
+
+	clusters := make([][]TeamCostID, len(medoids))
+	assigned := make([]bool, len(teams))
+
+	for _, streng := range []bool{true, false} {
+		for _, p := range paren {
+			if !assigned[p.team] && len(clusters[p.medoid]) < size && (!streng || past(clusters[p.medoid], teams[p.team])) {
+				clusters[p.medoid] = append(clusters[p.medoid], teams[p.team])
+				assigned[p.team] = true
+			}
+		}
+	}
+
+	var rest []TeamCostID
+	for t, ok := range assigned {
+		if !ok {
+			rest = append(rest, teams[t])
+		}
+	}
+
+	return clusters, rest
+}
+
+//zonder a cluster without the team at ix
+func zonder(cluster []TeamCostID, ix int) []TeamCostID {
+	result := make([]TeamCostID, 0, len(cluster)-1)
+	result = append(result, cluster[:ix]...)
+	return append(result, cluster[ix+1:]...)
+}
+
+//ruil the teams which don't past in their cluster with a team of another
+//cluster, when both past after the swap. Of the swaps the one adding the
+//least distance to the medoids is made
+func ruil(clusters [][]TeamCostID, medoids []TeamCostID) {
+	for a := range clusters {
+		for i := 0; i < len(clusters[a]); i++ {
+			x := clusters[a][i]
+
+			if past(zonder(clusters[a], i), x) {
+				continue
+			}
+
+			bestB, bestJ := -1, -1
+			bestAfstand := 0.0
+
+			for b := range clusters {
+				if b == a {
+					continue
+				}
+
+				for j, y := range clusters[b] {
+					if !past(zonder(clusters[a], i), y) || !past(zonder(clusters[b], j), x) {
+						continue
+					}
+
+					d := afstand(x, medoids[b]) + afstand(y, medoids[a])
+					if bestB < 0 || d < bestAfstand {
+						bestB, bestJ, bestAfstand = b, j, d
+					}
+				}
+			}
+
+			if bestB >= 0 {
+				clusters[a][i], clusters[bestB][bestJ] = clusters[bestB][bestJ], x
+			}
+		}
+	}
+}
+
+//medoid of a cluster, the team with the least total distance to the others
+func medoid(cluster []TeamCostID) TeamCostID {
+	best := cluster[0]
+	bestTotal := -1.0
+
+	for _, a := range cluster {
+		total := 0.0
+		for _, b := range cluster {
+			total += afstand(a, b)
+		}
+
+		if bestTotal < 0 || total < bestTotal {
+			best = a
+			bestTotal = total
+		}
+	}
+
+	return best
+}
+
+//ClusterKlasse into groepen of GroepGrootte teams by k-medoids on the travel
+//distance, keeping the teams of a vereniging apart and the P/D quota where
+//possible. The teams which don't fit a groep are returned as rest
+func ClusterKlasse(teams []TeamCostID, rng *rand.Rand) ([][]TeamCostID, []TeamCostID) {
+	k := len(teams) / GroepGrootte
+
+	if k == 0 {
+		return nil, teams
+	}
+
+	medoids := kiesMedoids(teams, k, rng)
+	clusters, rest := wijsToe(teams, medoids, GroepGrootte)
+
+	for i := 0; i < clusterIterations; i++ {
+		changed := false
+
+		for ix, cluster := range clusters {
+			if m := medoid(cluster); m != medoids[ix] {
+				medoids[ix] = m
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+
+		clusters, rest = wijsToe(teams, medoids, GroepGrootte)
+	}
+
+	ruil(clusters, medoids)
+
+	return clusters, rest
+}
+
+//MakeClusteredVector return a new solution with the teams of every klasse
+//clustered into regional groepen, slightly randomized by a few swaps between
+//groepen and random loten
+func MakeClusteredVector(rng *rand.Rand) gago.Genome {
+	vector := make(Vector, 0, len(optimizer.bond.teams))
+
+	for k := Meester; k <= Derde; k++ {
+		klasseTeams := optimizer.klasseGroups[k].teams
+		teams := make([]TeamCostID, len(klasseTeams))
+		for ix, v := range rng.Perm(len(klasseTeams)) {
+			teams[ix] = klasseTeams[v]
+		}
+
+		clusters, rest := ClusterKlasse(teams, rng)
+
+		if len(clusters) > 1 {
+			for s := rng.Intn(clusterSwaps + 1); s > 0; s-- {
+				a, b := rng.Intn(len(clusters)), rng.Intn(len(clusters))
+				i, j := rng.Intn(len(clusters[a])), rng.Intn(len(clusters[b]))
+				clusters[a][i], clusters[b][j] = clusters[b][j], clusters[a][i]
+			}
+		}
+
+		for _, cluster := range clusters {
+			for _, v := range rng.Perm(len(cluster)) {
+				vector = append(vector, cluster[v])
+			}
+		}
+
+		vector = append(vector, rest...)
+	}
+
+	optimizer.Repair(vector, rng)
+
+	return vector
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestClusterKlasse(t *testing.T) {
+	newTestOptimizer(t)
+	teams := optimizer.klasseGroups[Eerste].teams
+
+	for s := int64(1); s <= 20; s++ {
+		clusters, rest := ClusterKlasse(teams, rand.New(rand.NewSource(s)))
+
+		if len(clusters) != 2 || len(rest) != 0 {
+			t.Fatalf("seed %d: %d clusters and %d teams left", s, len(clusters), len(rest))
+		}
+
+		for _, cluster := range clusters {
+			verenigingen := make(map[string]bool)
+
+			for _, c := range cluster {
+				verenigingen[optimizer.matrix.GetTeamInfoByCostID(c).team.vereniging.id] = true
+			}
+
+			//the 20 teams of the Eerste klasse are of 15 verenigingen, 5 of them
+			//with 2 teams which belong in different groepen
+			if len(cluster) != GroepGrootte || len(verenigingen) != GroepGrootte {
+				t.Errorf("seed %d: cluster of %d teams of %d verenigingen", s, len(cluster), len(verenigingen))
+			}
+		}
+	}
+}
+
+func TestPast(t *testing.T) {
+	newTestOptimizer(t)
+	id := optimizer.matrix.GetTeamCostID
+
+	for teamID, pd := range map[string]Gradatie{"0100012": Promotie, "0100022": Kampioen, "0100032": Degradatie, "0100042": Promotie, "0100052": Degradatie} {
+		optimizer.matrix.GetTeamInfoByCostID(id(teamID)).team.pd = pd
+	}
+
+	tests := []struct {
+		naam    string
+		cluster []string
+		team    string
+		past    bool
+	}{
+		{"empty", nil, "0100112", true},
+		{"other verenigingen", []string{"0100012", "0100022"}, "0100112", true},
+		{"same vereniging", []string{"0100012", "0100111"}, "0100112", false},
+		{"second promovendus", []string{"0100012", "0100032"}, "0100042", true},
+		{"third promovendus", []string{"0100012", "0100022"}, "0100042", false},
+		{"first degradant", []string{"0100012", "0100022"}, "0100052", true},
+		{"second degradant", []string{"0100032"}, "0100052", false},
+	}
+
+	for _, test := range tests {
+		var cluster []TeamCostID
+		for _, c := range test.cluster {
+			cluster = append(cluster, id(c))
+		}
+
+		if p := past(cluster, id(test.team)); p != test.past {
+			t.Errorf("%v: past %v, expected %v", test.naam, p, test.past)
+		}
+	}
+}
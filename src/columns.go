@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+//Kolom of a sheet, found in the header row by one of its aliases. An alias
+//ending with * matches every header starting with it, when several headers
+//match the one of the oldest season (lowest remainder, e.g. 16/17 before
+//17/18) is used, or of the newest when nieuwste is set
+type Kolom struct {
+	naam      string
+	verplicht bool
+	nieuwste  bool
+}
+
+//kolomAliases by kolom naam, extended with LoadKolomAliases
+var kolomAliases = map[string][]string{
+	"teamid":     {"Teamid"},
+	"klasse":     {"zoek", "Klasse"},
+	"naam":       {"Teams", "Team"},
+	"vereniging": {"Id", "Club", "Clubnr"},
+	"plaats":     {"Plaats"},
+	"pd":         {"P/D"},
+	"groep":      {"kl*"},
+	"lot":        {"Lot*"},
+	"bond":       {"Bond"},
+	"wens":       {"Wens"},
+	"ander":      {"Team", "Ander team"},
+	"volgend":    {"volgend seizoen"},
+	"ronde":      {"Ronde"},
+	"thuis":      {"Thuis"},
+	"uit":        {"Uit"},
+}
+
+//LoadKolomAliases from a json file, e.g. { "teamid": ["Team nr"] }, the
+//aliases are tried after the kolom naam and before the default aliases
+func LoadKolomAliases(fileName string) error {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	var aliases map[string][]string
+	err = json.Unmarshal(file, &aliases)
+
+	if err != nil {
+		return err
+	}
+
+	for naam, extra := range aliases {
+		kolomAliases[naam] = append(extra, kolomAliases[naam]...)
+	}
+
+	return nil
+}
+
+//matchAlias returns the remainder of the header after a wildcard alias
+func matchAlias(header string, alias string) (string, bool) {
+	header = strings.TrimSpace(strings.ToLower(header))
+	alias = strings.ToLower(alias)
+
+	if strings.HasSuffix(alias, "*") {
+		prefix := strings.TrimSuffix(alias, "*")
+
+		if strings.HasPrefix(header, prefix) {
+			return strings.TrimSpace(header[len(prefix):]), true
+		}
+
+		return "", false
+	}
+
+	return "", header == alias
+}
+
+//findKolom in the cells of a header row, -1 if not found. Every kolom is
+//first found by its own naam, as used in csv and json files, so an alias like
+//kl* of groep does not pick the klasse column of such a file
+func findKolom(cells []*xlsx.Cell, kolom Kolom) int {
+	aliases := append([]string{kolom.naam}, kolomAliases[kolom.naam]...)
+
+	for _, alias := range aliases {
+		found := -1
+		var remainder string
+
+		for ix, cell := range cells {
+			r, ok := matchAlias(cell.Value, alias)
+
+			if ok && (found < 0 || (!kolom.nieuwste && r < remainder) || (kolom.nieuwste && r > remainder)) {
+				found = ix
+				remainder = r
+			}
+		}
+
+		if found >= 0 {
+			return found
+		}
+	}
+
+	return -1
+}
+
+//Kolommen maps kolom names to the index of the column in a sheet
+type Kolommen struct {
+	sheet   string
+	indices map[string]int
+	headers map[string]string
+}
+
+//FindKolommen in the header row of a sheet, fails if a required kolom is missing
+func FindKolommen(sheet string, header *xlsx.Row, kolommen []Kolom) (*Kolommen, error) {
+	result := new(Kolommen)
+	result.sheet = sheet
+	result.indices = make(map[string]int)
+	result.headers = make(map[string]string)
+
+	for _, k := range kolommen {
+		ix := findKolom(header.Cells, k)
+
+		if ix < 0 {
+			if k.verplicht {
+				return nil, fmt.Errorf("Sheet %v: missing column %v (%v)", sheet, k.naam, strings.Join(kolomAliases[k.naam], ", "))
+			}
+			continue
+		}
+
+		result.indices[k.naam] = ix
+		result.headers[k.naam] = header.Cells[ix].Value
+	}
+
+	return result, nil
+}
+
+//Has kolom in the sheet
+func (kolommen *Kolommen) Has(naam string) bool {
+	_, ok := kolommen.indices[naam]
+	return ok
+}
+
+//Header of a kolom as found in the sheet
+func (kolommen *Kolommen) Header(naam string) string {
+	return kolommen.headers[naam]
+}
+
+//Value of a kolom in a row, empty for missing koloms and short rows
+func (kolommen *Kolommen) Value(row *xlsx.Row, naam string) string {
+	ix, ok := kolommen.indices[naam]
+
+	if !ok || ix >= len(row.Cells) {
+		return ""
+	}
+
+	return row.Cells[ix].Value
+}
+
+//Cell reference of a kolom in a row for error messages, rows count from 1
+func (kolommen *Kolommen) Cell(rowNr int, naam string) string {
+	return fmt.Sprintf("%v!%v%d", kolommen.sheet, kolomLetter(kolommen.indices[naam]), rowNr)
+}
+
+//kolomLetter of a column index, 0 is A and 26 is AA
+func kolomLetter(ix int) string {
+	letter := string(rune('A' + ix%26))
+
+	if ix >= 26 {
+		return kolomLetter(ix/26-1) + letter
+	}
+
+	return letter
+}
+
+//FindHeader row of a sheet, the first row containing all required koloms
+func FindHeader(sheet *xlsx.Sheet, kolommen []Kolom) (int, *Kolommen, error) {
+	var first error
+
+	for ix, row := range sheet.Rows {
+		if len(row.Cells) == 0 {
+			continue
+		}
+
+		result, err := FindKolommen(sheet.Name, row, kolommen)
+
+		if err == nil {
+			return ix, result, nil
+		}
+
+		if first == nil {
+			first = fmt.Errorf("%v in header row %d", err, ix+1)
+		}
+	}
+
+	if first == nil {
+		first = fmt.Errorf("Sheet %v is empty", sheet.Name)
+	}
+
+	return -1, nil, first
+}
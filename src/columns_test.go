@@ -0,0 +1,110 @@
+package main
+
+import "testing"
+
+func TestFindKolom(t *testing.T) {
+	tests := []struct {
+		naam     string
+		nieuwste bool
+		headers  []string
+		ix       int
+	}{
+		{"teamid", false, []string{"Teamid", "zoek"}, 0},
+		{"teamid", false, []string{"zoek", "TEAMID "}, 1},
+		{"klasse", false, []string{"Teamid", "zoek", "kl16/17"}, 1},
+		{"klasse", false, []string{"Teamid", "zoek", "Klasse"}, 2},
+		{"klasse", false, []string{"teamid", "klasse"}, 1},
+		{"groep", false, []string{"lot17/18", "kl17/18", "kl16/17"}, 2},
+		{"groep", false, []string{"klasse", "groep"}, 1},
+		{"groep", false, []string{"klasse"}, 0},
+		{"lot", false, []string{"lot17/18", "Lot 16/17"}, 1},
+		{"lot", false, []string{"Lot 16/17", "lot"}, 1},
+		{"naam", false, []string{"Teams", "Team"}, 0},
+		{"bond", false, []string{"Teamid", "Teams"}, -1},
+		{"groep", true, []string{"lot17/18", "kl17/18", "kl16/17"}, 1},
+		{"lot", true, []string{"lot17/18", "Lot 16/17"}, 0},
+		{"groep", true, []string{"klasse", "groep", "kl17/18"}, 1},
+	}
+
+	for _, test := range tests {
+		row := sheetOfRecords("test", [][]string{test.headers}).Rows[0]
+
+		if ix := findKolom(row.Cells, Kolom{test.naam, false, test.nieuwste}); ix != test.ix {
+			t.Errorf("%v in %v: column %d, expected %d", test.naam, test.headers, ix, test.ix)
+		}
+	}
+}
+
+func TestFindHeader(t *testing.T) {
+	kolommen := []Kolom{{"teamid", true, false}, {"naam", true, false}, {"bond", false, false}}
+
+	tests := []struct {
+		naam     string
+		records  [][]string
+		headerIx int
+		bond     bool
+	}{
+		{"first row", [][]string{{"Teamid", "Teams"}, {"0100011", "V1"}}, 0, false},
+		{"title above the header", [][]string{{"Promovendi"}, {}, {"Teamid", "Teams", "Bond"}}, 2, true},
+		{"missing required column", [][]string{{"Teamid", "Bond"}}, -1, false},
+		{"empty", nil, -1, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			headerIx, found, err := FindHeader(sheetOfRecords("test", test.records), kolommen)
+
+			if headerIx != test.headerIx {
+				t.Fatalf("header row %d, expected %d (%v)", headerIx, test.headerIx, err)
+			}
+
+			if headerIx < 0 {
+				if err == nil {
+					t.Error("no error")
+				}
+				return
+			}
+
+			if found.Has("bond") != test.bond {
+				t.Errorf("bond column %v, expected %v", found.Has("bond"), test.bond)
+			}
+		})
+	}
+}
+
+func TestKolomLetter(t *testing.T) {
+	tests := map[int]string{0: "A", 7: "H", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+
+	for ix, letter := range tests {
+		if l := kolomLetter(ix); l != letter {
+			t.Errorf("%d: %v, expected %v", ix, l, letter)
+		}
+	}
+}
+
+func TestKolommenValue(t *testing.T) {
+	sheet := sheetOfRecords("Indeling", [][]string{{"Teamid", "Teams", "Plaats"}, {"0100011"}})
+	_, kolommen, err := FindHeader(sheet, []Kolom{{"teamid", true, false}, {"plaats", true, false}, {"bond", false, false}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := sheet.Rows[1]
+
+	if v := kolommen.Value(row, "teamid"); v != "0100011" {
+		t.Errorf("teamid %q", v)
+	}
+
+	if v := kolommen.Value(row, "plaats"); v != "" {
+		t.Errorf("plaats of a short row %q", v)
+	}
+
+	if v := kolommen.Value(row, "bond"); v != "" {
+		t.Errorf("missing bond column %q", v)
+	}
+
+	if c := kolommen.Cell(2, "plaats"); c != "Indeling!C2" {
+		t.Errorf("cell %v", c)
+	}
+}
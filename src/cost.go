@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+//TeamCost of a team in a groep, the travel terms of Optimizer.Evaluate
+type TeamCost struct {
+	Team string `json:"team"`
+	Naam string `json:"naam"`
+	Lot  int    `json:"lot"`
+	Reis *Reis  `json:"reis"`
+	//Cost of the team in the standaard objective
+	Cost uint64 `json:"cost"`
+}
+
+//Penalty that multiplied the cost of a groep
+type Penalty struct {
+	Reden      string  `json:"reden"`
+	Multiplier float64 `json:"multiplier"`
+	//Amount of cost added by the penalty
+	Amount uint64 `json:"amount"`
+}
+
+//GroepCost breakdown of Optimizer.Evaluate for a groep
+type GroepCost struct {
+	Groep        string     `json:"groep"`
+	Teams        []TeamCost `json:"teams"`
+	Promovendi   int        `json:"promovendi"`
+	Kampioenen   int        `json:"kampioenen"`
+	Degradanten  int        `json:"degradanten"`
+	Verenigingen int        `json:"verenigingen"`
+	//Objectives weighted terms by objective naam
+	Objectives map[string]float64 `json:"objectives"`
+	//TravelCost sum of the objectives, before penalties
+	TravelCost uint64 `json:"travelCost"`
+	//Herhalingen pairs of teams which shared a groep last season too
+	Herhalingen []string `json:"herhalingen"`
+	//HerhalingCost added to the travel cost for the herhalingen, before
+	//penalties
+	HerhalingCost uint64    `json:"herhalingCost"`
+	Penalties     []Penalty `json:"penalties"`
+	TotalCost     uint64    `json:"totalCost"`
+}
+
+//penalize cost with a multiplier
+func penalize(cost uint64, multiplier float64) uint64 {
+	return uint64(float64(cost) * multiplier)
+}
+
+//addPenalty that multiplied cost into penalized, the reden is only built by
+//the caller when explaining
+func (groepCost *GroepCost) addPenalty(reden string, multiplier float64, cost uint64, penalized uint64) {
+	groepCost.Penalties = append(groepCost.Penalties, Penalty{reden, multiplier, penalized - cost})
+}
+
+//WensCost of a wens, a wens not granted multiplies the total cost
+type WensCost struct {
+	Wens       string  `json:"wens"`
+	Granted    bool    `json:"granted"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+//KlasseMinimax term of a klasse, see Minimax
+type KlasseMinimax struct {
+	Klasse string `json:"klasse"`
+	//Waarde travel of the team at the percentiel
+	Waarde float64 `json:"waarde"`
+	Term   float64 `json:"term"`
+	//BovenCap teams travelling more than the cap
+	BovenCap []string `json:"bovenCap"`
+	//Overschrijding travel above the cap summed over the teams, an indeling
+	//with any is infeasible
+	Overschrijding float64 `json:"overschrijding"`
+}
+
+//CostBreakdown of Vector.Evaluate
+type CostBreakdown struct {
+	//Seed of the run which made the indeling
+	Seed    int64           `json:"seed"`
+	Groepen []*GroepCost    `json:"groepen"`
+	Minimax []KlasseMinimax `json:"minimax"`
+	Wensen  []WensCost      `json:"wensen"`
+	//ThuisConflicten of verenigingen whose teams should alternate thuis and
+	//uit, each pair of teams thuis multiplying the cost by the penalty
+	ThuisConflicten []ThuisConflict `json:"thuisConflicten"`
+	//Verhinderd teams playing thuis in a ronde they can't, each multiplying
+	//the cost by the unavailable penalty
+	Verhinderd []VerhinderdConflict `json:"verhinderd"`
+	//Wijzigingen of groep compared to the published indeling, each
+	//multiplying the cost by the change penalty
+	Wijzigingen []Wijziging `json:"wijzigingen"`
+	//GroepenCost sum of the cost of the groepen, before the minimax and
+	//the wensen
+	GroepenCost float64 `json:"groepenCost"`
+	Cost        float64 `json:"cost"`
+	//StopReason of the run which made the indeling
+	StopReason string `json:"stopReason,omitempty"`
+}
+
+//Explain the cost of the vector, term by term
+func (X Vector) Explain() *CostBreakdown {
+	breakdown := new(CostBreakdown)
+	breakdown.Seed = *seed
+	breakdown.StopReason = stopReason
+	X.evaluate(breakdown)
+	return breakdown
+}
+
+//ExportCostBreakdownJSON to a file
+func ExportCostBreakdownJSON(fileName string, breakdown *CostBreakdown) error {
+	b, err := json.MarshalIndent(breakdown, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, b, 0644)
+}
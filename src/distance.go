@@ -32,6 +32,11 @@ type DistanceMatrix struct {
 	citiesTravelInformation map[CityTrip]*TravelInformation
 }
 
+//CityName of a plaats as it is known in the matrix
+func CityName(plaats string) string {
+	return plaats + ", Netherlands"
+}
+
 //GetCityByID of matrix by ID
 func (distanceMatrix *DistanceMatrix) GetCityByID(ID CityID) *City {
 	return distanceMatrix.citiesByID[ID]
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tealeg/xlsx"
+)
+
+//addRow to a sheet, with a cell per value
+func addRow(sheet *xlsx.Sheet, values ...interface{}) {
+	row := sheet.AddRow()
+
+	for _, value := range values {
+		cell := row.AddCell()
+
+		switch v := value.(type) {
+		case int:
+			cell.SetInt(v)
+		case float64:
+			cell.SetFloat(v)
+		case string:
+			cell.SetString(v)
+		default:
+			cell.SetString(fmt.Sprint(v))
+		}
+	}
+}
+
+//groepen of the vector in order of position
+func (X Vector) groepen() []*Description {
+	result := make([]*Description, 0, len(X)/GroepGrootte)
+
+	var last *Description
+	for _, description := range optimizer.descriptions[:len(X)] {
+		if description != nil && description != last {
+			result = append(result, description)
+			last = description
+		}
+	}
+
+	return result
+}
+
+//ExportExcel of the indeling in the column layout of the Indeling sheet with
+//the new groep and lot of every team, a sheet per groep with its programma
+//and an Info sheet with the seed it was made with and why that run stopped
+func (X Vector) ExportExcel(fileName string) error {
+	file := xlsx.NewFile()
+
+	sheet, err := file.AddSheet("Indeling")
+
+	if err != nil {
+		return err
+	}
+
+	seizoen := optimizer.bond.seizoen
+	nieuwSeizoen := volgendSeizoen(seizoen)
+
+	if nieuwSeizoen == "" {
+		nieuwSeizoen = " nieuw"
+	}
+
+	addRow(sheet, "Teamid", "zoek", "lot"+nieuwSeizoen, "kl"+nieuwSeizoen, "Teams", "Id", "Plaats", "P/D", "kl"+seizoen, "pl.-Mp-Bp"+seizoen, "Lot "+seizoen)
+
+	for ix, tid := range X {
+		t := optimizer.matrix.GetTeamInfoByCostID(tid).team
+		description := optimizer.descriptions[ix]
+
+		groep := ""
+		lot := ""
+		if description != nil {
+			groep = description.Naam()
+			lot = strconv.Itoa(ix - description.begin + 1)
+		}
+
+		vorigLot := ""
+		if t.groep != "" {
+			vorigLot = strconv.Itoa(int(t.lot) + 1)
+		}
+
+		addRow(sheet, t.id, t.klasse.String(), lot, groep, t.naam, t.vereniging.id, t.vereniging.plaats, t.pd.Code(), t.groep, "", vorigLot)
+	}
+
+	groepSheets := make(map[string]*xlsx.Sheet)
+
+	for _, pw := range X.Programma() {
+		groepSheet := groepSheets[pw.Groep]
+
+		if groepSheet == nil {
+			groepSheet, err = file.AddSheet(pw.Groep)
+
+			if err != nil {
+				return err
+			}
+
+			addRow(groepSheet, "Ronde", "Lot", "Thuis", "Lot", "Uit", "Plaats", "Afstand (km)", "Reistijd (min)")
+			groepSheets[pw.Groep] = groepSheet
+		}
+
+		addRow(groepSheet, pw.Ronde, pw.ThuisLot, pw.ThuisNaam, pw.UitLot, pw.UitNaam, pw.Plaats, float64(pw.Afstand)/1000.0, float64(pw.Reistijd)/60.0)
+	}
+
+	info, err := file.AddSheet("Info")
+
+	if err != nil {
+		return err
+	}
+
+	//the seed as text, a number cell would round it to a float64
+	addRow(info, "Seed", strconv.FormatInt(*seed, 10))
+	addRow(info, "Cost", X.Evaluate())
+
+	if stopReason != "" {
+		addRow(info, "Stopped", stopReason)
+	}
+
+	return file.Save(fileName)
+}
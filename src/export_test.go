@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+func TestExportExcel(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+	//lot 1 and 2 of 1A change places
+	X[10], X[11] = X[11], X[10]
+
+	*seed = 42
+	stopReason = "interrupted at generation 7"
+	defer func() { *seed, stopReason = 0, "" }()
+
+	fileName, remove := writeTestFile(t, "indeling.xlsx", "")
+	defer remove()
+
+	if err := X.ExportExcel(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	//the teams as loaded, with the new groep and lot as the newest seizoen
+	sb, err := LoadSchaakbond(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indeling, err := LoadIndeling(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ix, tid := range X {
+		team := optimizer.matrix.GetTeamInfoByCostID(tid).team
+		description := optimizer.descriptions[ix]
+		loaded, ok := sb.teams[team.id]
+
+		if !ok || loaded.naam != team.naam || loaded.klasse != team.klasse || loaded.pd != team.pd ||
+			loaded.vereniging.id != team.vereniging.id || loaded.vereniging.plaats != team.vereniging.plaats {
+			t.Errorf("Team %v loaded as %+v", team.id, loaded)
+		}
+
+		if p := indeling.plaatsingen[team.id]; p.groep != description.Naam() || int(p.lot) != ix-description.begin {
+			t.Errorf("Team %v in groep %v lot %d, expected %v lot %d", team.id, p.groep, p.lot+1, description.Naam(), ix-description.begin+1)
+		}
+	}
+
+	file, err := xlsx.OpenFile(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := []string{"Teamid", "zoek", "lot17/18", "kl17/18", "Teams", "Id", "Plaats", "P/D", "kl16/17", "pl.-Mp-Bp16/17", "Lot 16/17"}
+	for ix, cell := range file.Sheet["Indeling"].Rows[0].Cells {
+		if ix >= len(header) || cell.Value != header[ix] {
+			t.Errorf("Header %d of Indeling is %v", ix+1, cell.Value)
+		}
+	}
+
+	//a header and a row per wedstrijd of the programma of every groep
+	w := testSchemaWedstrijden()[0]
+
+	for g, groep := range []string{"M", "1A", "1B"} {
+		sheet, ok := file.Sheet[groep]
+
+		if !ok || len(sheet.Rows) != 1+len(testSchemaWedstrijden()) {
+			t.Errorf("Sheet of groep %v missing or not 1+%d rows", groep, len(testSchemaWedstrijden()))
+			continue
+		}
+
+		thuis := optimizer.matrix.GetTeamInfoByCostID(X[g*GroepGrootte+w.Thuis-1]).team.naam
+		if row := sheet.Rows[1]; row.Cells[0].Value != "1" || row.Cells[1].Value != strconv.Itoa(w.Thuis) || row.Cells[2].Value != thuis {
+			t.Errorf("First wedstrijd of %v in ronde %v lot %v %v, expected ronde 1 lot %d %v", groep, row.Cells[0].Value, row.Cells[1].Value, row.Cells[2].Value, w.Thuis, thuis)
+		}
+	}
+
+	info := make(map[string]string)
+	for _, row := range file.Sheet["Info"].Rows {
+		info[row.Cells[0].Value] = row.Cells[1].Value
+	}
+
+	if info["Seed"] != "42" || info["Stopped"] != stopReason {
+		t.Errorf("Info %v", info)
+	}
+
+	if cost, err := strconv.ParseFloat(info["Cost"], 64); err != nil || cost != X.Evaluate() {
+		t.Errorf("Cost %v in the Info sheet, expected %v", info["Cost"], X.Evaluate())
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+//LoadSchaakbond from an excel, csv or json file, chosen by extension
+func LoadSchaakbond(fileName string) (*Schaakbond, error) {
+	return loadSchaakbond(fileName, false)
+}
+
+//loadSchaakbond with the groep and lot of the newest seizoen when nieuwste is
+//set, see loadSchaakbondSheet
+func loadSchaakbond(fileName string, nieuwste bool) (*Schaakbond, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return LoadSchaakbondCSV(fileName, nieuwste)
+	case ".json":
+		return LoadSchaakbondJSON(fileName)
+	}
+
+	return LoadSchaakbondExcel(fileName, nieuwste)
+}
+
+//LoadSpeelSchema from an excel, csv or json file, chosen by extension
+func LoadSpeelSchema(fileName string) (*SpeelSchema, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return LoadSpeelSchemaCSV(fileName)
+	case ".json":
+		return LoadSpeelSchemaJSON(fileName)
+	}
+
+	return LoadSpeelSchemaExcel(fileName)
+}
+
+//LoadWensen from an excel, csv or json file, chosen by extension
+func LoadWensen(fileName string) ([]Wens, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return LoadWensenCSV(fileName)
+	case ".json":
+		return LoadWensenJSON(fileName)
+	}
+
+	return LoadWensenExcel(fileName)
+}
+
+//LoadIndeling from an excel, csv or json file, of the newest seizoen in the
+//file: kl17/18 of a workbook written by --export-xlsx, which also has kl16/17.
+//When the newest seizoen has fouten or no team with a lot, as in the workbook
+//of the bond where kl17/18 holds the klasse before the indeling is made, the
+//indeling of the oldest seizoen is read
+func LoadIndeling(fileName string) (*Indeling, error) {
+	sb, err := loadSchaakbond(fileName, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if indeling := sb.HuidigeIndeling(); len(sb.fouten) == 0 && len(indeling.plaatsingen) > 0 {
+		return indeling, nil
+	}
+
+	sb, err = loadSchaakbond(fileName, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sb.fouten) > 0 {
+		return nil, fmt.Errorf("%v: %v", fileName, strings.Join(sb.fouten, "; "))
+	}
+
+	return sb.HuidigeIndeling(), nil
+}
+
+//readCSV records, separated by comma or by semicolon as written by a Dutch
+//excel
+func readCSV(fileName string) ([][]string, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(file))
+	reader.FieldsPerRecord = -1
+
+	firstLine := file
+	if nl := bytes.IndexByte(file, '\n'); nl >= 0 {
+		firstLine = file[:nl]
+	}
+
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	return reader.ReadAll()
+}
+
+//sheetOfRecords to reuse the excel loaders for other formats
+func sheetOfRecords(name string, records [][]string) *xlsx.Sheet {
+	sheet := new(xlsx.Sheet)
+	sheet.Name = name
+	sheet.Rows = make([]*xlsx.Row, len(records), len(records))
+
+	for ix, record := range records {
+		row := new(xlsx.Row)
+		row.Cells = make([]*xlsx.Cell, len(record), len(record))
+
+		for cx, value := range record {
+			row.Cells[cx] = &xlsx.Cell{Value: strings.TrimSpace(value)}
+		}
+
+		sheet.Rows[ix] = row
+	}
+
+	return sheet
+}
+
+//LoadSchaakbondCSV with a header row naming the columns like the Indeling
+//sheet or by their kolom names: teamid, klasse, naam, vereniging, plaats, pd,
+//groep, lot and bond
+func LoadSchaakbondCSV(fileName string, nieuwste bool) (*Schaakbond, error) {
+	records, err := readCSV(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loadSchaakbondSheet(sheetOfRecords(filepath.Base(fileName), records), nieuwste)
+}
+
+//TeamJSON is a team in a json teams file
+type TeamJSON struct {
+	Teamid     string `json:"teamid"`
+	Klasse     string `json:"klasse"`
+	Naam       string `json:"naam"`
+	Vereniging string `json:"vereniging"`
+	Plaats     string `json:"plaats"`
+	PD         string `json:"pd"`
+	Groep      string `json:"groep"`
+	Lot        int    `json:"lot"`
+	Bond       string `json:"bond"`
+}
+
+//LoadSchaakbondJSON with an object { "teams": [ TeamJSON, ... ] }
+func LoadSchaakbondJSON(fileName string) (*Schaakbond, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var content struct {
+		Teams []TeamJSON `json:"teams"`
+	}
+
+	err = json.Unmarshal(file, &content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(content.Teams)+1)
+	records = append(records, []string{"teamid", "klasse", "naam", "vereniging", "plaats", "pd", "groep", "lot", "bond"})
+
+	for _, t := range content.Teams {
+		lot := ""
+		if t.Lot > 0 {
+			lot = strconv.Itoa(t.Lot)
+		}
+
+		records = append(records, []string{t.Teamid, t.Klasse, t.Naam, t.Vereniging, t.Plaats, t.PD, t.Groep, lot, t.Bond})
+	}
+
+	return loadSchaakbondSheet(sheetOfRecords(filepath.Base(fileName), records), false)
+}
+
+//WedstrijdJSON is a wedstrijd in a csv or json schema file, ronde and loten
+//count from 1
+type WedstrijdJSON struct {
+	Ronde int `json:"ronde"`
+	Thuis int `json:"thuis"`
+	Uit   int `json:"uit"`
+}
+
+//speelSchemaOfWedstrijden checks every lot plays once in every ronde
+func speelSchemaOfWedstrijden(fileName string, wedstrijden []WedstrijdJSON) (*SpeelSchema, error) {
+	ss := new(SpeelSchema)
+	var count [len(ss.Rondes)]int
+	var played [len(ss.Rondes)][len(ss.Loten)]bool
+
+	for ix, w := range wedstrijden {
+		if w.Ronde < 1 || w.Ronde > len(ss.Rondes) {
+			return nil, fmt.Errorf("%v wedstrijd %d: ronde %d out of range", fileName, ix+1, w.Ronde)
+		}
+
+		if w.Thuis < 1 || w.Thuis > len(ss.Loten) || w.Uit < 1 || w.Uit > len(ss.Loten) || w.Thuis == w.Uit {
+			return nil, fmt.Errorf("%v wedstrijd %d: invalid loten %d - %d", fileName, ix+1, w.Thuis, w.Uit)
+		}
+
+		ronde := w.Ronde - 1
+		lotThuis := LotNummer(w.Thuis - 1)
+		lotUit := LotNummer(w.Uit - 1)
+
+		if played[ronde][lotThuis] || played[ronde][lotUit] || count[ronde] >= len(ss.Rondes[ronde].Wedstrijden) {
+			return nil, fmt.Errorf("%v wedstrijd %d: lot %d or %d plays twice in ronde %d", fileName, ix+1, w.Thuis, w.Uit, w.Ronde)
+		}
+
+		played[ronde][lotThuis] = true
+		played[ronde][lotUit] = true
+
+		ss.Rondes[ronde].Wedstrijden[count[ronde]].Thuis = lotThuis
+		ss.Rondes[ronde].Wedstrijden[count[ronde]].Uit = lotUit
+		ss.Loten[lotThuis].Rondes[ronde].Tegenstander = lotUit
+		ss.Loten[lotThuis].Rondes[ronde].Verplaatsing = Thuis
+		ss.Loten[lotUit].Rondes[ronde].Tegenstander = lotThuis
+		ss.Loten[lotUit].Rondes[ronde].Verplaatsing = Uit
+		count[ronde]++
+	}
+
+	for ronde, c := range count {
+		if c != len(ss.Rondes[ronde].Wedstrijden) {
+			return nil, fmt.Errorf("%v: ronde %d has %d wedstrijden instead of %d", fileName, ronde+1, c, len(ss.Rondes[ronde].Wedstrijden))
+		}
+	}
+
+	return ss, nil
+}
+
+//atoiKolom of a row, the error refers to the cell
+func atoiKolom(kolommen *Kolommen, row *xlsx.Row, rowNr int, naam string) (int, error) {
+	value, err := strconv.Atoi(kolommen.Value(row, naam))
+
+	if err != nil {
+		return 0, fmt.Errorf("%v: %v", kolommen.Cell(rowNr, naam), err)
+	}
+
+	return value, nil
+}
+
+//LoadSpeelSchemaCSV with the columns ronde, thuis and uit, one row per wedstrijd
+func LoadSpeelSchemaCSV(fileName string) (*SpeelSchema, error) {
+	records, err := readCSV(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := sheetOfRecords(filepath.Base(fileName), records)
+	headerIx, kolommen, err := FindHeader(sheet, []Kolom{
+		{"ronde", true, false},
+		{"thuis", true, false},
+		{"uit", true, false},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	wedstrijden := make([]WedstrijdJSON, 0, len(sheet.Rows))
+
+	for ix, row := range sheet.Rows[headerIx+1:] {
+		rowNr := headerIx + ix + 2
+
+		if kolommen.Value(row, "ronde") == "" {
+			continue
+		}
+
+		var w WedstrijdJSON
+
+		if w.Ronde, err = atoiKolom(kolommen, row, rowNr, "ronde"); err != nil {
+			return nil, err
+		}
+
+		if w.Thuis, err = atoiKolom(kolommen, row, rowNr, "thuis"); err != nil {
+			return nil, err
+		}
+
+		if w.Uit, err = atoiKolom(kolommen, row, rowNr, "uit"); err != nil {
+			return nil, err
+		}
+
+		wedstrijden = append(wedstrijden, w)
+	}
+
+	return speelSchemaOfWedstrijden(fileName, wedstrijden)
+}
+
+//LoadSpeelSchemaJSON with an object { "wedstrijden": [ WedstrijdJSON, ... ] }
+func LoadSpeelSchemaJSON(fileName string) (*SpeelSchema, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var content struct {
+		Wedstrijden []WedstrijdJSON `json:"wedstrijden"`
+	}
+
+	err = json.Unmarshal(file, &content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return speelSchemaOfWedstrijden(fileName, content.Wedstrijden)
+}
+
+//LoadWensenCSV with the columns teamid, wens and ander, one row per wens
+func LoadWensenCSV(fileName string) ([]Wens, error) {
+	records, err := readCSV(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loadWensenSheet(sheetOfRecords(filepath.Base(fileName), records))
+}
+
+//WensJSON is a wens in a json wensen file
+type WensJSON struct {
+	Teamid string `json:"teamid"`
+	Wens   string `json:"wens"`
+	Ander  string `json:"ander"`
+}
+
+//LoadWensenJSON with an object { "wensen": [ WensJSON, ... ] }
+func LoadWensenJSON(fileName string) ([]Wens, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var content struct {
+		Wensen []WensJSON `json:"wensen"`
+	}
+
+	err = json.Unmarshal(file, &content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(content.Wensen)+1)
+	records = append(records, []string{"teamid", "wens", "ander"})
+
+	for _, w := range content.Wensen {
+		records = append(records, []string{w.Teamid, w.Wens, w.Ander})
+	}
+
+	return loadWensenSheet(sheetOfRecords(filepath.Base(fileName), records))
+}
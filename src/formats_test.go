@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//writeTestFile in a temporary directory, removed with the returned function
+func writeTestFile(t *testing.T, name string, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "schaakschema")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, name)
+
+	if err := ioutil.WriteFile(fileName, []byte(content), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return fileName, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadSchaakbond(t *testing.T) {
+	tests := []struct {
+		naam    string
+		file    string
+		content string
+		//verwacht team 0100011, its groep and lot, and the bond of its vereniging
+		groep string
+		lot   LotNummer
+		bond  string
+		fout  string
+	}{
+		{"csv with kolom names", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd,groep,lot\n0100011,1,V1 1,010001,Plaats1,P,1B,4\n",
+			"1B", 3, "FSB", ""},
+		{"csv with semicolons", "teams.csv",
+			"teamid;klasse;naam;vereniging;plaats;pd;groep;lot\n0100011;1;V1 1;010001;Plaats1;;1B;4\n",
+			"1B", 3, "FSB", ""},
+		{"csv like the Indeling sheet", "teams.csv",
+			"Teamid,zoek,lot17/18,kl17/18,Teams,Id,Plaats,P/D,kl16/17,Lot 16/17\n0100011,1,,,V1 1,010001,Plaats1,,1C,7\n",
+			"1C", 6, "FSB", ""},
+		{"csv with header aliases", "teams.csv",
+			"Teamid,Klasse,Team,Club,Plaats,P/D\n0100011,1,V1 1,010001,Plaats1,\n",
+			"", 0, "FSB", ""},
+		{"csv with a bond column", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd,bond\n0100011,1,V1 1,990001,Plaats1,,SGA\n",
+			"", 0, "SGA", ""},
+		{"new team names its bond", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd,groep\n0100011,3,V1 1,990001,Plaats1,P,OSBO 1\n",
+			"", 0, "OSBO", ""},
+		{"blank groep", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd,groep\n0100011,3,V1 1,010001,Plaats1,P,\" \"\n",
+			"", 0, "FSB", ""},
+		{"json", "teams.json",
+			`{ "teams": [ { "teamid": "0100011", "klasse": "M", "naam": "V1 1", "vereniging": "010001", "plaats": "Plaats1", "pd": "D", "groep": "M", "lot": 10, "bond": "FSB" } ] }`,
+			"M", 9, "FSB", ""},
+		{"json without lot", "teams.json",
+			`{ "teams": [ { "teamid": "0100011", "klasse": "1", "naam": "V1 1", "vereniging": "010001", "plaats": "Plaats1", "groep": "1A" } ] }`,
+			"", 0, "", "teams.json!H2: Missing lot value"},
+		{"unknown klasse", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd\n0100011,4,V1 1,010001,Plaats1,\n",
+			"", 0, "", "teams.csv!B2: Unknown Klasse value"},
+		{"unknown P/D", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd\n0100011,1,V1 1,010001,Plaats1,X\n",
+			"", 0, "", "Unknown P/D value"},
+		{"lot out of range", "teams.csv",
+			"teamid,klasse,naam,vereniging,plaats,pd,groep,lot\n0100011,1,V1 1,010001,Plaats1,,1A,11\n",
+			"", 0, "", "Unknown lot value"},
+		{"missing column", "teams.csv",
+			"teamid,klasse,naam,vereniging,pd\n0100011,1,V1 1,010001,\n",
+			"", 0, "", "missing column plaats"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			fileName, remove := writeTestFile(t, test.file, test.content)
+			defer remove()
+
+			sb, err := LoadSchaakbond(fileName)
+
+			//a missing column fails, wrong values are collected as fouten
+			if err == nil && len(sb.fouten) > 0 {
+				err = fmt.Errorf("%v", strings.Join(sb.fouten, "; "))
+			}
+
+			if test.fout != "" {
+				if err == nil || !strings.Contains(err.Error(), test.fout) {
+					t.Fatalf("error %v, expected %v", err, test.fout)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			team, ok := sb.teams["0100011"]
+
+			if !ok {
+				t.Fatal("team 0100011 not loaded")
+			}
+
+			if team.groep != test.groep || team.lot != test.lot {
+				t.Errorf("groep %v lot %d, expected %v lot %d", team.groep, team.lot, test.groep, test.lot)
+			}
+
+			if naam := team.vereniging.bond.naam; naam != test.bond {
+				t.Errorf("bond %v, expected %v", naam, test.bond)
+			}
+		})
+	}
+}
+
+func TestLoadSpeelSchema(t *testing.T) {
+	var csv, json []string
+
+	for _, w := range testSchemaWedstrijden() {
+		csv = append(csv, strings.Join([]string{strconv.Itoa(w.Ronde), strconv.Itoa(w.Thuis), strconv.Itoa(w.Uit)}, ";"))
+		json = append(json, `{ "ronde": `+strconv.Itoa(w.Ronde)+`, "thuis": `+strconv.Itoa(w.Thuis)+`, "uit": `+strconv.Itoa(w.Uit)+` }`)
+	}
+
+	tests := []struct {
+		naam    string
+		file    string
+		content string
+		fout    string
+	}{
+		{"csv", "schema.csv", "Ronde;Thuis;Uit\n" + strings.Join(csv, "\n"), ""},
+		{"json", "schema.json", `{ "wedstrijden": [ ` + strings.Join(json, ", ") + ` ] }`, ""},
+		{"missing ronde", "schema.csv", "Ronde;Thuis;Uit\n" + strings.Join(csv[:40], "\n"), "ronde 9 has 0 wedstrijden"},
+		{"lot plays twice", "schema.csv", "Ronde;Thuis;Uit\n" + strings.Join(append([]string{"1;1;2"}, csv...), "\n"), "plays twice in ronde 1"},
+		{"lot out of range", "schema.csv", "Ronde;Thuis;Uit\n1;0;11\n", "invalid loten"},
+		{"not a number", "schema.csv", "Ronde;Thuis;Uit\n1;een;2\n", "schema.csv!B2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			fileName, remove := writeTestFile(t, test.file, test.content)
+			defer remove()
+
+			ss, err := LoadSpeelSchema(fileName)
+
+			if test.fout != "" {
+				if err == nil || !strings.Contains(err.Error(), test.fout) {
+					t.Fatalf("error %v, expected %v", err, test.fout)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *ss != *testSchema(t) {
+				t.Error("schema differs from the test schema")
+			}
+		})
+	}
+}
+
+func TestLoadWensen(t *testing.T) {
+	tests := []struct {
+		naam    string
+		file    string
+		content string
+		wensen  []Wens
+		fout    string
+	}{
+		{"csv", "wensen.csv", "teamid,wens,ander\n0100011,niet samen,0100012\n0100021,Samen,0100031\n",
+			[]Wens{{NietSamen, "0100011", "0100012", 2}, {Samen, "0100021", "0100031", 3}}, ""},
+		{"csv with headers", "wensen.csv", "Teamid;Wens;Ander team\n0100011;samen;0100012\n",
+			[]Wens{{Samen, "0100011", "0100012", 2}}, ""},
+		{"json", "wensen.json", `{ "wensen": [ { "teamid": "0100011", "wens": "niet samen", "ander": "0100012" } ] }`,
+			[]Wens{{NietSamen, "0100011", "0100012", 2}}, ""},
+		{"unknown wens", "wensen.csv", "teamid,wens,ander\n0100011,graag,0100012\n", nil, "Unknown Wens value"},
+		{"member list", "wensen.csv", "Naam,Plaats,RelatieNummer\nJansen,Enschede,1234\n", nil, "contains no wensen"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			fileName, remove := writeTestFile(t, test.file, test.content)
+			defer remove()
+
+			wensen, err := LoadWensen(fileName)
+
+			if test.fout != "" {
+				if err == nil || !strings.Contains(err.Error(), test.fout) {
+					t.Fatalf("error %v, expected %v", err, test.fout)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(wensen) != len(test.wensen) {
+				t.Fatalf("wensen %v, expected %v", wensen, test.wensen)
+			}
+
+			for ix, w := range wensen {
+				if w != test.wensen[ix] {
+					t.Errorf("wens %v (row %d), expected %v (row %d)", w, w.row, test.wensen[ix], test.wensen[ix].row)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadIndeling(t *testing.T) {
+	header := "Teamid,zoek,lot17/18,kl17/18,Teams,Id,Plaats,P/D,kl16/17,Lot 16/17\n"
+
+	tests := []struct {
+		naam    string
+		content string
+		groep   string
+		lot     LotNummer
+	}{
+		{"written by --export-xlsx", header + "0100011,1,4,1B,V1 1,010001,Plaats1,,1A,7\n", "1B", 3},
+		{"of the bond before the indeling is made", header + "0100011,1,,,V1 1,010001,Plaats1,,1A,7\n", "1A", 6},
+		{"of the bond with the klasse in the newest seizoen", header + "0100011,M,,M,V1 1,010001,Plaats1,,M,7\n0100012,1,,1,V1 2,010001,Plaats1,,1A,2\n", "M", 6},
+		{"kolom names", "teamid,klasse,naam,vereniging,plaats,pd,groep,lot\n0100011,1,V1 1,010001,Plaats1,,1C,2\n", "1C", 1},
+	}
+
+	for _, test := range tests {
+		fileName, remove := writeTestFile(t, "indeling.csv", test.content)
+		indeling, err := LoadIndeling(fileName)
+		remove()
+
+		if err != nil {
+			t.Errorf("%v: %v", test.naam, err)
+			continue
+		}
+
+		if p := indeling.plaatsingen["0100011"]; p.groep != test.groep || p.lot != test.lot {
+			t.Errorf("%v: groep %v lot %d, expected %v lot %d", test.naam, p.groep, p.lot, test.groep, test.lot)
+		}
+	}
+}
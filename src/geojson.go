@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+)
+
+//Coordinaat of a city, as found by the google geocoding API
+type Coordinaat struct {
+	City string
+	Lat  float64
+	Lng  float64
+}
+
+type geocodeResponse struct {
+	Results []struct {
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+	Status string `json:"status"`
+}
+
+func requestGeocode(apiKey string, city string) (*Coordinaat, error) {
+	//Example:
+	//https://maps.googleapis.com/maps/api/geocode/json?address=Apeldoorn&key=APIKEY
+
+	//Prevent exceding query limit by sleeping:
+	time.Sleep(200 * time.Millisecond)
+
+	var requestURL url.URL
+	requestURL.Scheme = "https"
+	requestURL.Host = "maps.googleapis.com"
+	requestURL.Path = "maps/api/geocode/json"
+	q := requestURL.Query()
+	q.Set("key", apiKey)
+	q.Set("address", city)
+	requestURL.RawQuery = q.Encode()
+
+	httpResponse, err := http.Get(requestURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	var response = new(geocodeResponse)
+	err = json.NewDecoder(httpResponse.Body).Decode(response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != "OK" || len(response.Results) == 0 {
+		return nil, errors.New("Geocode status invalid for " + city + ": " + response.Status)
+	}
+
+	coordinaat := new(Coordinaat)
+	coordinaat.City = city
+	coordinaat.Lat = response.Results[0].Geometry.Location.Lat
+	coordinaat.Lng = response.Results[0].Geometry.Location.Lng
+	return coordinaat, nil
+}
+
+//GetCoordinaten of cities, from the cache file or else by google geocoding
+//API. The cache is a json array of Coordinaat which can also be written by
+//hand, so no API key is needed when every city is present. On a failed
+//lookup the coordinates found so far are returned with the error
+func GetCoordinaten(cities []string, cacheFileName string, apiKey string) (map[string]Coordinaat, error) {
+	coordinaten := make(map[string]Coordinaat)
+
+	file, rerr := ioutil.ReadFile(cacheFileName)
+
+	if rerr == nil {
+		var cached []Coordinaat
+
+		if uerr := json.Unmarshal(file, &cached); uerr != nil {
+			return nil, uerr
+		}
+
+		for _, c := range cached {
+			coordinaten[c.City] = c
+		}
+	} else if !os.IsNotExist(rerr) {
+		return nil, rerr
+	}
+
+	sort.Strings(cities)
+	added := 0
+
+	//the error of the first city not found, returned with the coordinates found
+	var err error
+
+	for _, city := range cities {
+		if _, ok := coordinaten[city]; ok {
+			continue
+		}
+
+		coordinaat, gerr := requestGeocode(apiKey, city)
+
+		if gerr != nil {
+			//keep what is found so far, the next run continues from it
+			log.Print(gerr)
+			err = gerr
+			break
+		}
+
+		coordinaten[city] = *coordinaat
+		added++
+	}
+
+	if added > 0 {
+		cached := make([]Coordinaat, 0, len(coordinaten))
+		for _, c := range coordinaten {
+			cached = append(cached, c)
+		}
+		sort.Slice(cached, func(i, j int) bool { return cached[i].City < cached[j].City })
+
+		b, merr := json.MarshalIndent(cached, "", "  ")
+
+		if merr != nil {
+			return nil, merr
+		}
+
+		if werr := ioutil.WriteFile(cacheFileName, b, 0644); werr != nil {
+			return nil, werr
+		}
+	}
+
+	return coordinaten, err
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+//geoPosition of a plaats as GeoJSON [longitude, latitude]
+func geoPosition(coordinaten map[string]Coordinaat, plaats string) ([]float64, bool) {
+	c, ok := coordinaten[CityName(plaats)]
+	return []float64{c.Lng, c.Lat}, ok
+}
+
+//ExportGeoJSON of the indeling as a FeatureCollection with a point per team
+//at the plaats of its vereniging and a line per uit wedstrijd of the
+//programma, with the seed of the run as a member of the collection
+func (X Vector) ExportGeoJSON(fileName string, coordinaten map[string]Coordinaat) error {
+	features := make([]geoJSONFeature, 0, len(X)+len(X)/2*len(optimizer.schema.Rondes))
+
+	for ix, tid := range X {
+		t := optimizer.matrix.GetTeamInfoByCostID(tid).team
+		point, ok := geoPosition(coordinaten, t.vereniging.plaats)
+
+		if !ok {
+			log.Printf("No coordinates of %v, team %v is left out", t.vereniging.plaats, t.id)
+			continue
+		}
+
+		properties := map[string]interface{}{
+			"team":       t.id,
+			"naam":       t.naam,
+			"vereniging": t.vereniging.id,
+			"plaats":     t.vereniging.plaats,
+			"klasse":     t.klasse.String(),
+		}
+
+		if description := optimizer.descriptions[ix]; description != nil {
+			properties["groep"] = description.Naam()
+			properties["lot"] = ix - description.begin + 1
+		}
+
+		features = append(features, geoJSONFeature{"Feature", geoJSONGeometry{"Point", point}, properties})
+	}
+
+	for _, pw := range X.Programma() {
+		uit := optimizer.bond.teams[pw.Uit]
+		from, fromOk := geoPosition(coordinaten, uit.vereniging.plaats)
+		to, toOk := geoPosition(coordinaten, pw.Plaats)
+
+		if !fromOk || !toOk {
+			continue
+		}
+
+		features = append(features, geoJSONFeature{"Feature", geoJSONGeometry{"LineString", [][]float64{from, to}}, map[string]interface{}{
+			"groep":    pw.Groep,
+			"ronde":    pw.Ronde,
+			"thuis":    pw.Thuis,
+			"uit":      pw.Uit,
+			"afstand":  pw.Afstand,
+			"reistijd": pw.Reistijd,
+		}})
+	}
+
+	b, err := json.MarshalIndent(struct {
+		Type     string           `json:"type"`
+		Seed     int64            `json:"seed"`
+		Features []geoJSONFeature `json:"features"`
+	}{"FeatureCollection", *seed, features}, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, b, 0644)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetCoordinatenFromCache(t *testing.T) {
+	cacheFileName, remove := writeTestFile(t, "geocode.cache", `[ { "City": "Plaats1", "Lat": 52.1, "Lng": 5.1 }, { "City": "Plaats2", "Lat": 52.2, "Lng": 5.2 } ]`)
+	defer remove()
+
+	tests := []struct {
+		naam     string
+		cache    string
+		cities   []string
+		gevonden int
+	}{
+		{"every city cached", cacheFileName, []string{"Plaats2", "Plaats1"}, 2},
+		{"no cities without a cache", filepath.Join(filepath.Dir(cacheFileName), "missing.cache"), nil, 0},
+	}
+
+	for _, test := range tests {
+		coordinaten, err := GetCoordinaten(test.cities, test.cache, "")
+
+		if err != nil {
+			t.Errorf("%v: %v", test.naam, err)
+		}
+
+		if len(coordinaten) != test.gevonden {
+			t.Errorf("%v: %d coordinates, expected %d", test.naam, len(coordinaten), test.gevonden)
+		}
+	}
+}
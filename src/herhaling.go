@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+//Herhaling of last season's opponents: every pair of teams which shared a
+//groep last season and shares one again adds the kilometers to the travel
+//cost of its groep, converted with the cost per km of last season so the
+//term weighs the same whatever the objectives and the groep
+type Herhaling struct {
+	groepen    map[TeamCostID]string
+	kilometers float64
+	perKm      float64
+}
+
+//NewHerhaling of the indeling of last season, teams which are no longer in
+//the bond are ignored. The cost per km is the travel cost of the groepen of X,
+//the vector of last season, per km travelled, before any penalty
+func (optimizer *Optimizer) NewHerhaling(lastSeason *Indeling, X Vector, kilometers float64) (*Herhaling, error) {
+	var travelCost, meters uint64
+
+	for i := 0; i < len(X)/GroepGrootte; i++ {
+		travelCosts := optimizer.evaluate(X[i*GroepGrootte:(i+1)*GroepGrootte], nil)
+		travelCost += optimizer.objectives.Cost(travelCosts.Reizen, nil)
+		meters += travelCosts.TotalDistance
+	}
+
+	if meters == 0 {
+		return nil, fmt.Errorf("No km travelled last season to convert the km of a repeated pair to cost")
+	}
+
+	herhaling := new(Herhaling)
+	herhaling.groepen = make(map[TeamCostID]string)
+	herhaling.kilometers = kilometers
+	herhaling.perKm = float64(travelCost) / (float64(meters) / 1000.0)
+
+	for teamID, p := range lastSeason.plaatsingen {
+		if _, ok := optimizer.bond.teams[teamID]; ok {
+			herhaling.groepen[optimizer.matrix.GetTeamCostID(teamID)] = p.groep
+		}
+	}
+
+	return herhaling, nil
+}
+
+//zonderHerhaling is the cost of X without the repeated opponents, to compare
+//with the baseline of last season which shares every groep again
+func (X Vector) zonderHerhaling() float64 {
+	herhaling := optimizer.herhaling
+	optimizer.herhaling = nil
+	defer func() { optimizer.herhaling = herhaling }()
+
+	return X.Evaluate()
+}
+
+//pairs of teams of a groep which shared a groep last season
+func (herhaling *Herhaling) pairs(teams []TeamCostID) [][2]TeamCostID {
+	var pairs [][2]TeamCostID
+
+	for a := 0; a < len(teams); a++ {
+		groep, ok := herhaling.groepen[teams[a]]
+
+		if !ok {
+			continue
+		}
+
+		for b := a + 1; b < len(teams); b++ {
+			if other, ok := herhaling.groepen[teams[b]]; ok && other == groep {
+				pairs = append(pairs, [2]TeamCostID{teams[a], teams[b]})
+			}
+		}
+	}
+
+	return pairs
+}
+
+//cost of the pairs of a groep
+func (herhaling *Herhaling) cost(pairs int) uint64 {
+	return uint64(float64(pairs) * herhaling.kilometers * herhaling.perKm)
+}
+
+//paar of team ids, sorted
+func (herhaling *Herhaling) paar(pair [2]TeamCostID) string {
+	ids := []string{
+		optimizer.matrix.GetTeamInfoByCostID(pair[0]).team.id,
+		optimizer.matrix.GetTeamInfoByCostID(pair[1]).team.id,
+	}
+	sort.Strings(ids)
+	return ids[0] + "-" + ids[1]
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"testing"
+)
+
+//testLastSeason with groep A of 3 and groep B of 2 teams of klasse 1, and a
+//team which left the bond
+func testLastSeason() *Indeling {
+	indeling := new(Indeling)
+	indeling.plaatsingen = make(map[string]Plaatsing)
+
+	for _, p := range []Plaatsing{
+		{"0100012", "1A", Eerste, 1},
+		{"0100022", "1A", Eerste, 2},
+		{"0100032", "1A", Eerste, 3},
+		{"0100042", "1B", Eerste, 1},
+		{"0100052", "1B", Eerste, 2},
+		{"0109991", "1B", Eerste, 3},
+	} {
+		indeling.plaatsingen[p.teamID] = p
+	}
+
+	return indeling
+}
+
+func TestHerhalingPairs(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	herhaling, err := optimizer.NewHerhaling(testLastSeason(), X, 25)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		naam  string
+		teams []string
+		pairs int
+	}{
+		{"groep A", []string{"0100012", "0100022", "0100032"}, 3},
+		{"groep A and B", []string{"0100012", "0100022", "0100042", "0100052", "0100062"}, 2},
+		{"one of each", []string{"0100012", "0100042", "0100062"}, 0},
+		{"not last season", []string{"0100062", "0100072"}, 0},
+	}
+
+	for _, test := range tests {
+		teams := make([]TeamCostID, len(test.teams))
+		for ix, teamID := range test.teams {
+			teams[ix] = optimizer.matrix.GetTeamCostID(teamID)
+		}
+
+		if pairs := herhaling.pairs(teams); len(pairs) != test.pairs {
+			t.Errorf("%v: pairs %v, expected %d", test.naam, pairs, test.pairs)
+		}
+	}
+}
+
+func TestHerhalingCost(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	if _, err := optimizer.NewHerhaling(testLastSeason(), Vector{}, 25); err == nil {
+		t.Error("No error without km travelled")
+	}
+
+	herhaling, err := optimizer.NewHerhaling(testLastSeason(), X, 25)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the travel cost per km before the penalties of the groepen
+	var travelCost, meters uint64
+	for i := 0; i < len(X)/GroepGrootte; i++ {
+		groepCost := new(GroepCost)
+		travelCosts := optimizer.evaluate(X[i*GroepGrootte:(i+1)*GroepGrootte], groepCost)
+		travelCost += groepCost.TravelCost
+		meters += travelCosts.TotalDistance
+	}
+
+	if perKm := float64(travelCost) / (float64(meters) / 1000.0); herhaling.perKm <= 0 || herhaling.perKm != perKm {
+		t.Fatalf("Cost per km %v, expected %v", herhaling.perKm, perKm)
+	}
+
+	if c := herhaling.cost(0); c != 0 {
+		t.Errorf("Cost of no pairs %d", c)
+	}
+
+	//the same per pair in every groep, whatever it travels
+	for pairs := 1; pairs <= 3; pairs++ {
+		if c := herhaling.cost(pairs); c != uint64(float64(pairs)*25*herhaling.perKm) {
+			t.Errorf("Cost of %d pairs %d", pairs, c)
+		}
+	}
+
+	//4 pairs in the first groep of klasse 1, none in the second
+	optimizer.herhaling = herhaling
+
+	for ix, pairs := range []int{4, 0} {
+		groepCost := new(GroepCost)
+		optimizer.evaluate(X[10*(ix+1):10*(ix+2)], groepCost)
+
+		if len(groepCost.Herhalingen) != pairs || groepCost.HerhalingCost != herhaling.cost(pairs) {
+			t.Errorf("Groep %d: herhalingen %v costing %d, expected %d", ix+1, groepCost.Herhalingen, groepCost.HerhalingCost, pairs)
+		}
+	}
+
+	//compared with the baseline without the repeated opponents
+	zonder := X.zonderHerhaling()
+
+	if optimizer.herhaling != herhaling {
+		t.Error("Herhaling not restored")
+	}
+
+	optimizer.herhaling = nil
+	if c := X.Evaluate(); c != zonder {
+		t.Errorf("Cost without herhaling %v, expected %v", zonder, c)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sort"
+)
+
+//Herstel of a published indeling after teams withdrew, were added or moved
+//klasse: every team which changes groep multiplies the cost by the penalty,
+//so the optimizer weighs the changes against the travel cost
+type Herstel struct {
+	groepen map[TeamCostID]*Description
+	penalty float64
+}
+
+//Wijziging of the groep of a team compared to the published indeling
+type Wijziging struct {
+	Team string `json:"team"`
+	Van  string `json:"van"`
+	Naar string `json:"naar"`
+}
+
+//NewHerstel of the published indeling, logging the changes of the teams
+//since it was published
+func (optimizer *Optimizer) NewHerstel(published *Indeling, penalty float64) *Herstel {
+	herstel := new(Herstel)
+	herstel.groepen = make(map[TeamCostID]*Description)
+	herstel.penalty = penalty
+
+	var teruggetrokken, nieuw, verplaatst []string
+
+	for teamID, p := range published.plaatsingen {
+		t, ok := optimizer.bond.teams[teamID]
+
+		if !ok {
+			teruggetrokken = append(teruggetrokken, teamID)
+			continue
+		}
+
+		if t.klasse != p.klasse {
+			verplaatst = append(verplaatst, teamID)
+			continue
+		}
+
+		//a groep which no longer exists, e.g. after a klasse shrunk, is a
+		//change for all its teams whatever the indeling
+		if description := optimizer.findGroep(p.groep); description != nil {
+			herstel.groepen[optimizer.matrix.GetTeamCostID(teamID)] = description
+		}
+	}
+
+	for teamID := range optimizer.bond.teams {
+		if _, ok := published.plaatsingen[teamID]; !ok {
+			nieuw = append(nieuw, teamID)
+		}
+	}
+
+	sort.Strings(teruggetrokken)
+	sort.Strings(nieuw)
+	sort.Strings(verplaatst)
+
+	log.Printf("Since publication %d teams withdrew %v, %d were added %v and %d moved klasse %v",
+		len(teruggetrokken), teruggetrokken, len(nieuw), nieuw, len(verplaatst), verplaatst)
+
+	return herstel
+}
+
+//changes of groep of the teams of the vector which keep their klasse
+func (herstel *Herstel) changes(X Vector) int {
+	count := 0
+
+	for ix, tid := range X {
+		if published, ok := herstel.groepen[tid]; ok && published != optimizer.descriptions[ix] {
+			count++
+		}
+	}
+
+	return count
+}
+
+//multiplier of the cost of the vector for its changes
+func (herstel *Herstel) multiplier(X Vector) float64 {
+	return math.Pow(herstel.penalty, float64(herstel.changes(X)))
+}
+
+//Wijzigingen of the vector compared to the published indeling
+func (herstel *Herstel) Wijzigingen(X Vector) []Wijziging {
+	var wijzigingen []Wijziging
+
+	for ix, tid := range X {
+		published, ok := herstel.groepen[tid]
+
+		if !ok || published == optimizer.descriptions[ix] {
+			continue
+		}
+
+		naar := ""
+		if optimizer.descriptions[ix] != nil {
+			naar = optimizer.descriptions[ix].Naam()
+		}
+
+		wijzigingen = append(wijzigingen, Wijziging{optimizer.matrix.GetTeamInfoByCostID(tid).team.id, published.Naam(), naar})
+	}
+
+	return wijzigingen
+}
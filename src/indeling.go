@@ -0,0 +1,69 @@
+package main
+
+import "sort"
+
+//Plaatsing of a team in a groep of an indeling
+type Plaatsing struct {
+	teamID string
+	groep  string
+	klasse Klasse
+	lot    LotNummer
+}
+
+//Indeling of teams in groepen and loten
+type Indeling struct {
+	plaatsingen map[string]Plaatsing
+}
+
+//VectorFromIndeling builds a vector from the indeling of last season. Teams
+//staying in their klasse keep their groep and lot where possible, promoted,
+//relegated and new teams fill the remaining positions of their new klasse
+func (optimizer *Optimizer) VectorFromIndeling(indeling *Indeling) Vector {
+	vector := make([]TeamCostID, len(optimizer.descriptions), len(optimizer.descriptions))
+	filled := make([]bool, len(optimizer.descriptions), len(optimizer.descriptions))
+
+	for k := Meester; k <= Derde; k++ {
+		klasseGroup := optimizer.klasseGroups[k]
+		groepen := make(map[string][]Plaatsing)
+		rest := make([]TeamCostID, 0, len(klasseGroup.teams))
+
+		for _, t := range optimizer.bond.klasses[k] {
+			p, ok := indeling.plaatsingen[t.id]
+
+			if ok && p.klasse == k {
+				groepen[p.groep] = append(groepen[p.groep], p)
+			} else {
+				rest = append(rest, optimizer.matrix.GetTeamCostID(t.id))
+			}
+		}
+
+		labels := make([]string, 0, len(groepen))
+		for label := range groepen {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+
+		for gi, label := range labels {
+			for _, p := range groepen[label] {
+				position := klasseGroup.begin + (gi * 10) + int(p.lot)
+
+				if gi < len(klasseGroup.teams)/10 && !filled[position] {
+					vector[position] = optimizer.matrix.GetTeamCostID(p.teamID)
+					filled[position] = true
+				} else {
+					rest = append(rest, optimizer.matrix.GetTeamCostID(p.teamID))
+				}
+			}
+		}
+
+		for position := klasseGroup.begin; position <= klasseGroup.end; position++ {
+			if !filled[position] {
+				vector[position] = rest[0]
+				filled[position] = true
+				rest = rest[1:]
+			}
+		}
+	}
+
+	return Vector(vector)
+}
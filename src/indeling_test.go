@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+//eersteTeams of the test teams in the order of their ids
+func eersteTeams() []string {
+	var teamIDs []string
+
+	for v := 1; v <= 15; v++ {
+		if v > 10 {
+			teamIDs = append(teamIDs, fmt.Sprintf("0100%02d1", v))
+		}
+		teamIDs = append(teamIDs, fmt.Sprintf("0100%02d2", v))
+	}
+
+	return teamIDs
+}
+
+func TestVectorFromIndeling(t *testing.T) {
+	newTestOptimizer(t)
+	eerste := eersteTeams()
+
+	plaats := func(teamID string, groep string, klasse Klasse, lot int) Plaatsing {
+		return Plaatsing{teamID, groep, klasse, LotNummer(lot)}
+	}
+
+	tests := []struct {
+		naam        string
+		plaatsingen []Plaatsing
+		//verwacht team id by position, positions not listed are free
+		verwacht map[int]string
+	}{
+		{"empty", nil, map[int]string{}},
+		{"staying teams keep groep and lot",
+			[]Plaatsing{plaats(eerste[0], "1A", Eerste, 9), plaats(eerste[1], "1B", Eerste, 0), plaats("0100011", "M", Meester, 4)},
+			map[int]string{19: eerste[0], 20: eerste[1], 4: "0100011"}},
+		{"groepen by their order",
+			[]Plaatsing{plaats(eerste[0], "1B", Eerste, 2), plaats(eerste[1], "1C", Eerste, 3)},
+			map[int]string{12: eerste[0], 23: eerste[1]}},
+		{"promoted team fills a free position",
+			[]Plaatsing{plaats(eerste[0], "2A", Tweede, 0), plaats(eerste[1], "1A", Eerste, 0)},
+			map[int]string{10: eerste[1]}},
+		{"second team on a lot fills a free position",
+			[]Plaatsing{plaats(eerste[0], "1A", Eerste, 5), plaats(eerste[1], "1A", Eerste, 5)},
+			map[int]string{15: eerste[0]}},
+		{"groep beyond the groepen of the klasse",
+			[]Plaatsing{plaats(eerste[0], "1A", Eerste, 0), plaats(eerste[1], "1B", Eerste, 0), plaats(eerste[2], "1C", Eerste, 0)},
+			map[int]string{10: eerste[0], 20: eerste[1]}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			indeling := new(Indeling)
+			indeling.plaatsingen = make(map[string]Plaatsing)
+
+			for _, p := range test.plaatsingen {
+				indeling.plaatsingen[p.teamID] = p
+			}
+
+			X := optimizer.VectorFromIndeling(indeling)
+
+			//every team once, on a position of its klasse
+			if _, err := optimizer.VectorFromTeamIDs(X.TranslateToTeamIDs()); err != nil {
+				t.Fatal(err)
+			}
+
+			teamIDs := X.TranslateToTeamIDs()
+
+			for position, teamID := range test.verwacht {
+				if teamIDs[position] != teamID {
+					t.Errorf("position %d: team %v, expected %v", position, teamIDs[position], teamID)
+				}
+			}
+		})
+	}
+}
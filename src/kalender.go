@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Kalender of a seizoen, dates are given as 2006-01-02 and times as 15:04 in
+//the Europe/Amsterdam time zone
+type Kalender struct {
+	//Rondes date of every ronde
+	Rondes []string `json:"rondes"`
+	//Klasses dates of every ronde of a klasse { M, 1, 2, 3 }, replacing Rondes
+	Klasses map[string][]string `json:"klasses"`
+	//Centraal played ronde of the Meester klasse
+	Centraal *CentraleRonde `json:"centraal"`
+	//Aanvang of the wedstrijden, 13:00 by default
+	Aanvang string `json:"aanvang"`
+	//Duur of a wedstrijd, 6h by default
+	Duur string `json:"duur"`
+	//Speellokalen address by vereniging id, the plaats is used when missing
+	Speellokalen map[string]string `json:"speellokalen"`
+
+	location *time.Location
+	duur     time.Duration
+}
+
+//CentraleRonde of the Meester klasse, played at one location
+type CentraleRonde struct {
+	Ronde int    `json:"ronde"`
+	Datum string `json:"datum"`
+	Adres string `json:"adres"`
+}
+
+//LoadKalender from a json file
+func LoadKalender(fileName string) (*Kalender, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	kalender := new(Kalender)
+	err = json.Unmarshal(file, kalender)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if kalender.Aanvang == "" {
+		kalender.Aanvang = "13:00"
+	}
+
+	if kalender.Duur == "" {
+		kalender.Duur = "6h"
+	}
+
+	kalender.duur, err = time.ParseDuration(kalender.Duur)
+
+	if err != nil {
+		return nil, err
+	}
+
+	kalender.location, err = time.LoadLocation("Europe/Amsterdam")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return kalender, nil
+}
+
+//isCentraal if the ronde of the klasse is played at the central location
+func (kalender *Kalender) isCentraal(klasse Klasse, ronde int) bool {
+	return kalender.Centraal != nil && klasse == Meester && kalender.Centraal.Ronde == ronde
+}
+
+//Aanvangstijd of a ronde (counting from 1) of a klasse
+func (kalender *Kalender) Aanvangstijd(klasse Klasse, ronde int) (time.Time, error) {
+	var datum string
+
+	if kalender.isCentraal(klasse, ronde) && kalender.Centraal.Datum != "" {
+		datum = kalender.Centraal.Datum
+	} else {
+		rondes := kalender.Rondes
+
+		if klasseRondes, ok := kalender.Klasses[klasse.String()]; ok {
+			rondes = klasseRondes
+		}
+
+		if ronde < 1 || ronde > len(rondes) {
+			return time.Time{}, fmt.Errorf("No date of ronde %d of klasse %v in kalender", ronde, klasse)
+		}
+
+		datum = rondes[ronde-1]
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04", datum+" "+kalender.Aanvang, kalender.location)
+}
+
+//Locatie of a wedstrijd
+func (kalender *Kalender) Locatie(klasse Klasse, ronde int, thuis Team) string {
+	if kalender.isCentraal(klasse, ronde) && kalender.Centraal.Adres != "" {
+		return kalender.Centraal.Adres
+	}
+
+	if adres, ok := kalender.Speellokalen[thuis.vereniging.id]; ok {
+		return adres
+	}
+
+	return thuis.vereniging.plaats
+}
+
+//icsEscape text of a property value
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+//icsLine folded at 75 octets
+func icsLine(builder *bytes.Buffer, line string) {
+	for len(line) > 75 {
+		cut := 75
+		//don't split a multi byte character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		builder.WriteString(line[:cut] + "\r\n")
+		line = " " + line[cut:]
+	}
+	builder.WriteString(line + "\r\n")
+}
+
+//ics calendar of the wedstrijden of a set of teams
+func (kalender *Kalender) ics(naam string, programma []ProgrammaWedstrijd, teamIDs map[string]bool) string {
+	var builder bytes.Buffer
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	icsLine(&builder, "BEGIN:VCALENDAR")
+	icsLine(&builder, "VERSION:2.0")
+	icsLine(&builder, "PRODID:-//Phact//Schaakindeling//NL")
+	icsLine(&builder, "X-WR-CALNAME:"+icsEscape(naam))
+	icsLine(&builder, "X-SCHAAKINDELING-SEED:"+strconv.FormatInt(*seed, 10))
+
+	for _, pw := range programma {
+		for _, teamID := range []string{pw.Thuis, pw.Uit} {
+			if !teamIDs[teamID] {
+				continue
+			}
+
+			team := optimizer.bond.teams[teamID]
+			thuis := optimizer.bond.teams[pw.Thuis]
+			aanvang, _ := kalender.Aanvangstijd(team.klasse, pw.Ronde)
+
+			tegenstander := pw.UitNaam
+			uitThuis := "thuis"
+			if teamID == pw.Uit {
+				tegenstander = pw.ThuisNaam
+				uitThuis = "uit"
+			}
+
+			icsLine(&builder, "BEGIN:VEVENT")
+			icsLine(&builder, fmt.Sprintf("UID:%s-%d-%s@schaakindeling", pw.Groep, pw.Ronde, teamID))
+			icsLine(&builder, "DTSTAMP:"+stamp)
+			icsLine(&builder, "DTSTART:"+aanvang.UTC().Format("20060102T150405Z"))
+			icsLine(&builder, "DTEND:"+aanvang.Add(kalender.duur).UTC().Format("20060102T150405Z"))
+			icsLine(&builder, "SUMMARY:"+icsEscape(pw.ThuisNaam+" - "+pw.UitNaam))
+			icsLine(&builder, "LOCATION:"+icsEscape(kalender.Locatie(team.klasse, pw.Ronde, thuis)))
+			icsLine(&builder, "DESCRIPTION:"+icsEscape(fmt.Sprintf("Groep %s ronde %d, %s %s tegen %s", pw.Groep, pw.Ronde, team.naam, uitThuis, tegenstander)))
+			icsLine(&builder, "END:VEVENT")
+		}
+	}
+
+	icsLine(&builder, "END:VCALENDAR")
+	return builder.String()
+}
+
+//Check the date of every ronde of the klasses with teams, so exporting
+//can't fail after optimizing
+func (kalender *Kalender) Check(sb *Schaakbond, ss *SpeelSchema) error {
+	for k := Meester; k <= Derde; k++ {
+		if len(sb.klasses[k]) == 0 {
+			continue
+		}
+
+		for ronde := 1; ronde <= len(ss.Rondes); ronde++ {
+			if _, err := kalender.Aanvangstijd(k, ronde); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+//ExportICS of every team and every vereniging into a directory
+func (kalender *Kalender) ExportICS(directory string, programma []ProgrammaWedstrijd) error {
+	err := kalender.Check(optimizer.bond, optimizer.schema)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(directory, 0755)
+
+	if err != nil {
+		return err
+	}
+
+	for _, t := range optimizer.bond.teams {
+		content := kalender.ics(t.naam, programma, map[string]bool{t.id: true})
+
+		if err = ioutil.WriteFile(filepath.Join(directory, "team-"+t.id+".ics"), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range optimizer.bond.verenigingen {
+		teamIDs := make(map[string]bool)
+		for _, t := range v.teams {
+			teamIDs[t.id] = true
+		}
+
+		content := kalender.ics("Vereniging "+v.id+" "+v.plaats, programma, teamIDs)
+
+		if err = ioutil.WriteFile(filepath.Join(directory, "vereniging-"+v.id+".ics"), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIcsLine(t *testing.T) {
+	tests := []struct {
+		naam  string
+		line  string
+		lines []string
+	}{
+		{"short", "SUMMARY:V1 1 - V2 1", []string{"SUMMARY:V1 1 - V2 1"}},
+		{"75 octets", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"76 octets", strings.Repeat("a", 76), []string{strings.Repeat("a", 75), " a"}},
+		{"folded twice", strings.Repeat("a", 160), []string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " " + strings.Repeat("a", 11)}},
+		//é is 2 octets, the 75th octet is its first
+		{"multi byte character", strings.Repeat("a", 74) + "é", []string{strings.Repeat("a", 74), " é"}},
+	}
+
+	for _, test := range tests {
+		var buffer bytes.Buffer
+		icsLine(&buffer, test.line)
+
+		lines := strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n")
+
+		if strings.Join(lines, "|") != strings.Join(test.lines, "|") {
+			t.Errorf("%v: %q, expected %q", test.naam, lines, test.lines)
+		}
+
+		for _, line := range lines {
+			if len(line) > 75 {
+				t.Errorf("%v: line of %d octets", test.naam, len(line))
+			}
+		}
+
+		//unfolding gives the line back
+		if unfolded := strings.Replace(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n ", "", -1); unfolded != test.line {
+			t.Errorf("%v: unfolded %q", test.naam, unfolded)
+		}
+	}
+}
+
+func TestIcsEscape(t *testing.T) {
+	tests := map[string]string{
+		"Caissa 2":          "Caissa 2",
+		"Kerkstraat 1, Ede": `Kerkstraat 1\, Ede`,
+		"a;b":               `a\;b`,
+		`c:\schaak`:         `c:\\schaak`,
+		"regel\nregel":      `regel\nregel`,
+	}
+
+	for text, escaped := range tests {
+		if e := icsEscape(text); e != escaped {
+			t.Errorf("%q: %q, expected %q", text, e, escaped)
+		}
+	}
+}
+
+func TestKalenderCheck(t *testing.T) {
+	sb, err := loadSchaakbondSheet(sheetOfRecords("test", testRecords()), false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rondes := []string{"2017-09-16", "2017-10-07", "2017-11-04", "2017-11-25", "2017-12-16", "2018-01-20", "2018-02-10", "2018-03-10", "2018-04-14"}
+
+	tests := []struct {
+		naam     string
+		kalender Kalender
+		fout     string
+	}{
+		{"every ronde", Kalender{Rondes: rondes}, ""},
+		{"missing ronde", Kalender{Rondes: rondes[:8]}, "No date of ronde 9 of klasse M"},
+		{"dates of a klasse", Kalender{Rondes: rondes, Klasses: map[string][]string{"1": rondes[:8]}}, "No date of ronde 9 of klasse 1"},
+		//klasse 2 has no teams
+		{"klasse without teams", Kalender{Rondes: rondes, Klasses: map[string][]string{"2": nil}}, ""},
+		{"centrale ronde", Kalender{Rondes: rondes[:8], Klasses: map[string][]string{"1": rondes}, Centraal: &CentraleRonde{9, "2018-04-21", "Utrecht"}}, ""},
+		{"wrong date", Kalender{Rondes: append([]string{"16-09-2017"}, rondes[1:]...)}, "cannot parse"},
+	}
+
+	for _, test := range tests {
+		k := test.kalender
+		k.Aanvang = "13:00"
+		k.location = time.UTC
+
+		err := k.Check(sb, testSchema(t))
+
+		if test.fout == "" && err != nil {
+			t.Errorf("%v: %v", test.naam, err)
+		}
+
+		if test.fout != "" && (err == nil || !strings.Contains(err.Error(), test.fout)) {
+			t.Errorf("%v: error %v, expected %v", test.naam, err, test.fout)
+		}
+	}
+}
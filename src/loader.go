@@ -4,143 +4,472 @@ import (
 	"fmt"
 
 	"strconv"
+	"strings"
 
 	"github.com/tealeg/xlsx"
 )
 
-//LoadSchaakbondExcel Laad teams en verenigingen uit het excel-bestand
-func LoadSchaakbondExcel(fileName string) (*Schaakbond, error) {
+//parseKlasse { M, 1, 2, 3 }, a groep like 1A is parsed as its klasse
+func parseKlasse(value string) (Klasse, bool) {
+	if len(value) == 0 {
+		return Meester, false
+	}
+
+	switch value[0] {
+	case 'M':
+		return Meester, true
+	case '1':
+		return Eerste, true
+	case '2':
+		return Tweede, true
+	case '3':
+		return Derde, true
+	}
+
+	return Meester, false
+}
+
+//isGroep { M, 1A, 1B, 2A, ..., 3H }
+func isGroep(value string) bool {
+	if value == "M" {
+		return true
+	}
+
+	_, ok := parseKlasse(value)
+	return ok && len(value) == 2 && value[1] >= 'A' && value[1] <= 'Z'
+}
+
+//LoadSchaakbondExcel Laad teams en verenigingen uit het excel-bestand, met
+//groep en lot van het nieuwste seizoen als nieuwste is gezet
+func LoadSchaakbondExcel(fileName string, nieuwste bool) (*Schaakbond, error) {
 	xlFile, err := xlsx.OpenFile(fileName)
 
 	if err != nil {
 		return nil, err
 	}
 
+	for _, sheet := range xlFile.Sheets {
+		if sheet.Name == "Indeling" {
+			return loadSchaakbondSheet(sheet, nieuwste)
+		}
+	}
+
+	return nil, fmt.Errorf("No sheet Indeling found")
+}
+
+//loadSchaakbondSheet fails only without its header, a wrong value in a row is
+//collected in the fouten of the schaakbond so Validate reports all of them.
+//The groep and lot are of the oldest seizoen in the sheet, the current one of
+//the teams file, or of the newest when nieuwste is set
+func loadSchaakbondSheet(sheet *xlsx.Sheet, nieuwste bool) (*Schaakbond, error) {
 	sb := new(Schaakbond)
+	sb.bonden = make(map[string]*Bond)
 	sb.verenigingen = make(map[string]Vereniging)
 	sb.teams = make(map[string]Team)
 	sb.klasses = make(map[Klasse][]Team)
 
-	for _, sheet := range xlFile.Sheets {
-		if sheet.Name == "Indeling" {
-			for _, row := range sheet.Rows {
-				//0 - Team Id
-				//1 - Team Klasse { M, 1, 2, 3 }
-				//4 - Team Naam
-				//5 - Vereniging Id
-				//6 - Vereniging plaats
-				//7 - Team D/P/_
-
-				if row.Cells[0].Value != "" &&
-					row.Cells[0].Value != "Teamid" {
-
-					v, ok := sb.verenigingen[row.Cells[5].Value]
-
-					if !ok {
-						var nw Vereniging
-						nw.id = row.Cells[5].Value
-						nw.plaats = row.Cells[6].Value
-						nw.teams = make(map[string]Team)
-						sb.verenigingen[nw.id] = nw
-						v = nw
-					}
-
-					var t Team
-					t.id = row.Cells[0].Value
-					t.naam = row.Cells[4].Value
-
-					switch row.Cells[1].Value {
-					case "M":
-						t.klasse = Meester
-					case "1":
-						t.klasse = Eerste
-					case "2":
-						t.klasse = Tweede
-					case "3":
-						t.klasse = Derde
-					default:
-						return nil, fmt.Errorf("Unknown Klasse value of team %v (%v)", row.Cells[0].Value, row.Cells[1].Value)
-					}
-
-					switch row.Cells[7].Value {
-					case "K":
-						t.pd = Kampioen
-					case "P":
-						t.pd = Promotie
-					case "D":
-						t.pd = Degradatie
-					case "":
-						t.pd = Ongewijzigd
-					default:
-						return nil, fmt.Errorf("Unknown P/D value of team %v (%v)", row.Cells[0].Value, row.Cells[7].Value)
-					}
-
-					t.vereniging = v
-					v.teams[t.id] = t
-					sb.teams[t.id] = t
-
-					klasseTeams := sb.klasses[t.klasse]
-
-					if klasseTeams == nil {
-						klasseTeams = make([]Team, 0, 256)
-					}
-
-					sb.klasses[t.klasse] = append(klasseTeams, t)
+	//teamid - Team Id
+	//klasse - Team Klasse { M, 1, 2, 3 }
+	//naam - Team Naam
+	//vereniging - Vereniging Id
+	//plaats - Vereniging plaats
+	//pd - Team D/P/_
+	//groep - Groep huidig seizoen { M, 1A, ... } of Bond van nieuwe teams { FSB, OSBO 1, ... }
+	//lot - Lot huidig seizoen
+	//bond - Bond van de vereniging, optional: without it the bond follows from
+	//the first two digits of the Vereniging Id, see bondNamen
+	headerIx, kolommen, err := FindHeader(sheet, []Kolom{
+		{"teamid", true, false},
+		{"klasse", true, false},
+		{"naam", true, false},
+		{"vereniging", true, false},
+		{"plaats", true, false},
+		{"pd", true, false},
+		{"groep", false, nieuwste},
+		{"lot", false, nieuwste},
+		{"bond", false, false},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sb.sheet = sheet.Name
+	sb.seizoen = parseSeizoen(kolommen.Header("groep"))
+
+	for ix, row := range sheet.Rows[headerIx+1:] {
+		rowNr := headerIx + ix + 2
+
+		if kolommen.Value(row, "teamid") == "" {
+			continue
+		}
+
+		verenigingID := kolommen.Value(row, "vereniging")
+
+		sb.regels = append(sb.regels, Regel{rowNr, kolommen.Value(row, "teamid"), verenigingID, kolommen.Value(row, "plaats")})
+
+		//duplicates are reported by Validate
+		if _, dubbel := sb.teams[kolommen.Value(row, "teamid")]; dubbel {
+			continue
+		}
+
+		if verenigingID == "" {
+			sb.fouten = append(sb.fouten, fmt.Sprintf("%v: missing Vereniging Id of team %v", kolommen.Cell(rowNr, "vereniging"), kolommen.Value(row, "teamid")))
+			continue
+		}
+
+		klasse, ok := parseKlasse(kolommen.Value(row, "klasse"))
+
+		if !ok {
+			sb.fouten = append(sb.fouten, fmt.Sprintf("%v: Unknown Klasse value of team %v (%v)", kolommen.Cell(rowNr, "klasse"), kolommen.Value(row, "teamid"), kolommen.Value(row, "klasse")))
+			continue
+		}
+
+		v, ok := sb.verenigingen[verenigingID]
+
+		if !ok {
+			var nw Vereniging
+			nw.id = verenigingID
+			nw.plaats = kolommen.Value(row, "plaats")
+			if bond := strings.TrimSpace(kolommen.Value(row, "bond")); bond != "" {
+				nw.bond = sb.GetOrAddNamedBond(bond)
+			} else {
+				nw.bond = sb.GetOrAddBond(nw.id)
+			}
+			nw.teams = make(map[string]Team)
+			sb.verenigingen[nw.id] = nw
+			v = nw
+		}
+
+		var t Team
+		t.id = kolommen.Value(row, "teamid")
+		t.naam = kolommen.Value(row, "naam")
+		t.klasse = klasse
+
+		switch kolommen.Value(row, "pd") {
+		case "K":
+			t.pd = Kampioen
+		case "P":
+			t.pd = Promotie
+		case "D":
+			t.pd = Degradatie
+		case "":
+			t.pd = Ongewijzigd
+		default:
+			sb.fouten = append(sb.fouten, fmt.Sprintf("%v: Unknown P/D value of team %v (%v)", kolommen.Cell(rowNr, "pd"), t.id, kolommen.Value(row, "pd")))
+		}
+
+		if groep := strings.TrimSpace(kolommen.Value(row, "groep")); groep != "" {
+			if isGroep(groep) {
+				lotValue := kolommen.Value(row, "lot")
+				lot, err := strconv.ParseUint(lotValue, 10, 8)
+
+				switch {
+				case lotValue == "":
+					sb.fouten = append(sb.fouten, fmt.Sprintf("%v: Missing lot value of team %v in groep %v", kolommen.Cell(rowNr, "lot"), t.id, groep))
+				case err != nil || lot < 1 || lot > 10:
+					sb.fouten = append(sb.fouten, fmt.Sprintf("%v: Unknown lot value of team %v (%v)", kolommen.Cell(rowNr, "lot"), t.id, lotValue))
+				default:
+					t.groep = groep
+					t.lot = LotNummer(lot - 1)
 				}
+			} else if v.bond.naam == v.bond.id {
+				//name the bond by the column, e.g. "OSBO 1" is the first klasse of OSBO
+				v.bond.naam = strings.Fields(groep)[0]
 			}
 		}
+
+		t.vereniging = v
+		v.teams[t.id] = t
+		sb.teams[t.id] = t
+
+		klasseTeams := sb.klasses[t.klasse]
+
+		if klasseTeams == nil {
+			klasseTeams = make([]Team, 0, 256)
+		}
+
+		sb.klasses[t.klasse] = append(klasseTeams, t)
 	}
 
 	return sb, nil
 }
 
-//LoadSpeelSchemaExcel Laad speel schema excel-bestand
-func LoadSpeelSchemaExcel(fileName string) (*SpeelSchema, error) {
+//LoadWensenExcel Laad de wensen uit het "Wensen" werkblad, of het enige
+//werkblad van het bestand. The WL sheet of Indeling.xlsx is a list of members,
+//the bond keeps the wensen elsewhere
+func LoadWensenExcel(fileName string) ([]Wens, error) {
 	xlFile, err := xlsx.OpenFile(fileName)
 
 	if err != nil {
 		return nil, err
 	}
 
-	ss := new(SpeelSchema)
+	for _, sheet := range xlFile.Sheets {
+		if sheet.Name == "Wensen" || len(xlFile.Sheets) == 1 {
+			return loadWensenSheet(sheet)
+		}
+	}
+
+	return nil, fmt.Errorf("No sheet Wensen found in %v", fileName)
+}
+
+//loadWensenSheet with the kolommen Teamid, Wens { samen, niet samen } en Ander
+//team (het andere Teamid)
+func loadWensenSheet(sheet *xlsx.Sheet) ([]Wens, error) {
+	headerIx, kolommen, err := FindHeader(sheet, []Kolom{
+		{"teamid", true, false},
+		{"wens", true, false},
+		{"ander", true, false},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Sheet %v contains no wensen: %v", sheet.Name, err)
+	}
+
+	wensen := make([]Wens, 0, len(sheet.Rows))
+
+	for ix, row := range sheet.Rows[headerIx+1:] {
+		if kolommen.Value(row, "teamid") == "" {
+			continue
+		}
+
+		var w Wens
+		w.teamID = kolommen.Value(row, "teamid")
+		w.anderTeamID = kolommen.Value(row, "ander")
+		w.row = headerIx + ix + 2
+
+		switch strings.ToLower(kolommen.Value(row, "wens")) {
+		case "samen":
+			w.soort = Samen
+		case "niet samen":
+			w.soort = NietSamen
+		default:
+			return nil, fmt.Errorf("%v: Unknown Wens value (%v)", kolommen.Cell(w.row, "wens"), kolommen.Value(row, "wens"))
+		}
+
+		wensen = append(wensen, w)
+	}
+
+	return wensen, nil
+}
+
+//Promovendus uit een regionale bond naar de Derde klasse
+type Promovendus struct {
+	bond, verenigingID, naam, plaats string
+	row                              int
+}
+
+//LoadPromovendiExcel Laad de promovendi uit het "promovendi" werkblad
+func LoadPromovendiExcel(fileName string) ([]Promovendus, error) {
+	xlFile, err := xlsx.OpenFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
 
 	for _, sheet := range xlFile.Sheets {
-		if len(sheet.Rows) >= 7 &&
-			len(sheet.Rows[0].Cells) > 0 &&
-			sheet.Rows[0].Cells[0].Value == "Ronde" { //should be the wright sheet
-			for ix, row := range sheet.Rows {
-				if ix > 1 && ix < 7 {
-					for ronde := 0; ronde < 9; ronde++ {
+		if sheet.Name == "promovendi" {
+			//bond - Bond { FSB, OSBO 1, ... }
+			//vereniging - Club (Vereniging Id)
+			//naam - Team Naam
+			//plaats - Plaats
+			headerIx, kolommen, err := FindHeader(sheet, []Kolom{
+				{"bond", true, false},
+				{"vereniging", true, false},
+				{"naam", true, false},
+				{"plaats", false, false},
+			})
+
+			if err != nil {
+				return nil, err
+			}
 
-						thuis, err := strconv.ParseUint(row.Cells[1+(3*ronde)].Value, 10, 16)
+			promovendi := make([]Promovendus, 0, 32)
 
-						if err != nil {
-							return nil, err
-						}
+			for ix, row := range sheet.Rows[headerIx+1:] {
+				//the first table ends at the first empty row
+				if kolommen.Value(row, "vereniging") == "" {
+					break
+				}
 
-						uit, err := strconv.ParseUint(row.Cells[2+(3*ronde)].Value, 10, 16)
+				var p Promovendus
+				p.bond = kolommen.Value(row, "bond")
+				p.verenigingID = kolommen.Value(row, "vereniging")
+				p.naam = kolommen.Value(row, "naam")
+				p.plaats = kolommen.Value(row, "plaats")
+				p.row = headerIx + ix + 2
+				promovendi = append(promovendi, p)
+			}
+
+			return promovendi, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No sheet promovendi found")
+}
+
+//Eindstand van een team in het afgelopen seizoen
+type Eindstand struct {
+	naam, verenigingID, groep string
+	kampioen, degradant       bool
+	//inVolgendeKlasse is false when the team leaves the competition
+	volgendeKlasse   Klasse
+	inVolgendeKlasse bool
+	row              int
+}
+
+//LoadEindstandenExcel Laad de eindstanden uit het "eindstanden" werkblad
+func LoadEindstandenExcel(fileName string) ([]Eindstand, error) {
+	xlFile, err := xlsx.OpenFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
 
-						if err != nil {
-							return nil, err
-						}
+	for _, sheet := range xlFile.Sheets {
+		if strings.HasPrefix(sheet.Name, "eindstanden") {
+			//naam - Team Naam
+			//vereniging - Vereniging Id
+			//pd - K/D/_
+			//groep - Groep { M, 1A, ... }
+			//volgend - Klasse volgend seizoen { M, 1, 2, 3 } of Bond
+			headerIx, kolommen, err := FindHeader(sheet, []Kolom{
+				{"naam", true, false},
+				{"vereniging", true, false},
+				{"pd", true, false},
+				{"groep", true, false},
+				{"volgend", true, false},
+			})
 
-						lotThuis := LotNummer(thuis - 1)
-						lotUit := LotNummer(uit - 1)
+			if err != nil {
+				return nil, err
+			}
 
-						ss.Rondes[ronde].Wedstrijden[ix-2].Thuis = lotThuis
-						ss.Rondes[ronde].Wedstrijden[ix-2].Uit = lotUit
-						ss.Loten[lotThuis].Rondes[ronde].Tegenstander = lotUit
-						ss.Loten[lotThuis].Rondes[ronde].Verplaatsing = Thuis
-						ss.Loten[lotUit].Rondes[ronde].Tegenstander = lotThuis
-						ss.Loten[lotUit].Rondes[ronde].Verplaatsing = Uit
+			eindstanden := make([]Eindstand, 0, len(sheet.Rows))
 
-					}
+			for ix, row := range sheet.Rows[headerIx+1:] {
+				if kolommen.Value(row, "vereniging") == "" {
+					continue
 				}
+
+				var e Eindstand
+				e.naam = kolommen.Value(row, "naam")
+				e.verenigingID = kolommen.Value(row, "vereniging")
+				e.kampioen = kolommen.Value(row, "pd") == "K"
+				e.degradant = kolommen.Value(row, "pd") == "D"
+				e.groep = kolommen.Value(row, "groep")
+				e.volgendeKlasse, e.inVolgendeKlasse = parseKlasse(kolommen.Value(row, "volgend"))
+				e.row = headerIx + ix + 2
+				eindstanden = append(eindstanden, e)
 			}
 
-			return ss, nil
+			return eindstanden, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No sheet eindstanden found")
+}
+
+//LoadSpeelSchemaExcel Laad speel schema excel-bestand
+func LoadSpeelSchemaExcel(fileName string) (*SpeelSchema, error) {
+	xlFile, err := xlsx.OpenFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sheet := range xlFile.Sheets {
+		//should be the wright sheet
+		headerIx, kolommen, err := FindHeader(sheet, []Kolom{{"ronde", true, false}})
+
+		if err == nil {
+			return loadSpeelSchemaSheet(sheet, headerIx, kolommen)
 		}
 	}
 
 	return nil, fmt.Errorf("No suitable data found")
 }
+
+//loadSpeelSchemaSheet with a row of ronde numbers, followed by a row with the
+//Thuis and Uit columns of every ronde and a row per wedstrijd
+func loadSpeelSchemaSheet(sheet *xlsx.Sheet, headerIx int, kolommen *Kolommen) (*SpeelSchema, error) {
+	ss := new(SpeelSchema)
+
+	if len(sheet.Rows) < headerIx+2+len(ss.Rondes[0].Wedstrijden) {
+		return nil, fmt.Errorf("Sheet %v: expected %d wedstrijden below row %d", sheet.Name, len(ss.Rondes[0].Wedstrijden), headerIx+2)
+	}
+
+	rondeRow := sheet.Rows[headerIx]
+	uitThuisRow := sheet.Rows[headerIx+1]
+
+	for ronde := 0; ronde < len(ss.Rondes); ronde++ {
+		thuisKolom := -1
+		uitKolom := -1
+
+		for cx, cell := range rondeRow.Cells {
+			if cx == kolommen.indices["ronde"] || cell.Value != strconv.Itoa(ronde+1) {
+				continue
+			}
+
+			//the Thuis and Uit columns follow the ronde number until the next ronde
+			for ux := cx; ux < len(uitThuisRow.Cells) && (ux == cx || ux >= len(rondeRow.Cells) || rondeRow.Cells[ux].Value == ""); ux++ {
+				if _, ok := matchAlias(uitThuisRow.Cells[ux].Value, "Thuis"); ok && thuisKolom < 0 {
+					thuisKolom = ux
+				}
+				if _, ok := matchAlias(uitThuisRow.Cells[ux].Value, "Uit"); ok && uitKolom < 0 {
+					uitKolom = ux
+				}
+			}
+		}
+
+		if thuisKolom < 0 || uitKolom < 0 {
+			return nil, fmt.Errorf("Sheet %v: missing Thuis/Uit columns of ronde %d in rows %d-%d", sheet.Name, ronde+1, headerIx+1, headerIx+2)
+		}
+
+		for w := range ss.Rondes[ronde].Wedstrijden {
+			rowNr := headerIx + 3 + w
+			row := sheet.Rows[rowNr-1]
+
+			lotThuis, err := parseLot(row, thuisKolom)
+
+			if err != nil {
+				return nil, fmt.Errorf("Sheet %v: %v%d: %v", sheet.Name, kolomLetter(thuisKolom), rowNr, err)
+			}
+
+			lotUit, err := parseLot(row, uitKolom)
+
+			if err != nil {
+				return nil, fmt.Errorf("Sheet %v: %v%d: %v", sheet.Name, kolomLetter(uitKolom), rowNr, err)
+			}
+
+			ss.Rondes[ronde].Wedstrijden[w].Thuis = lotThuis
+			ss.Rondes[ronde].Wedstrijden[w].Uit = lotUit
+			ss.Loten[lotThuis].Rondes[ronde].Tegenstander = lotUit
+			ss.Loten[lotThuis].Rondes[ronde].Verplaatsing = Thuis
+			ss.Loten[lotUit].Rondes[ronde].Tegenstander = lotThuis
+			ss.Loten[lotUit].Rondes[ronde].Verplaatsing = Uit
+		}
+	}
+
+	return ss, nil
+}
+
+//parseLot { 1 .. 10 } of a cell
+func parseLot(row *xlsx.Row, cx int) (LotNummer, error) {
+	if cx >= len(row.Cells) {
+		return 0, fmt.Errorf("missing lot")
+	}
+
+	lot, err := strconv.ParseUint(row.Cells[cx].Value, 10, 8)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if lot < 1 || lot > 10 {
+		return 0, fmt.Errorf("lot %d out of range", lot)
+	}
+
+	return LotNummer(lot - 1), nil
+}
@@ -1,30 +1,164 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/MaxHalford/gago"
 )
 
 var optimizer *Optimizer
 
+var seed = flag.Int64("seed", 0, "seed of the random number generator, 0 picks one from the clock")
+var checkpointFileName = flag.String("checkpoint", "checkpoint.json", "file to periodically write the populations to")
+var checkpointEvery = flag.Int("checkpoint-every", 10000, "generations between two checkpoints, 0 disables checkpoints")
+var resumeFileName = flag.String("resume", "", "checkpoint file to resume a run from, with the same inputs and settings")
+var initialFileName = flag.String("initial", "", "checkpoint file whose best division seeds a new run")
+var maxGenerations = flag.Int("max-generations", 5000000, "stop at this generation, 0 disables the limit")
+var maxDuration = flag.Duration("max-duration", 0, "stop after this wall-clock time, e.g. 8h, 0 disables the limit")
+var maxStagnation = flag.Int("max-stagnation", 0, "stop after this many generations without improvement, 0 disables the limit")
+var targetCost = flag.Float64("target-cost", 0, "stop once the best cost is at or below this value, 0 disables the target")
+var exportXlsxFileName = flag.String("export-xlsx", "", "excel file to write the best indeling to")
+var scheduleCSVFileName = flag.String("schedule-csv", "", "csv file to write the programma of the best indeling to")
+var scheduleJSONFileName = flag.String("schedule-json", "", "json file to write the programma of the best indeling to")
+var explainFileName = flag.String("explain", "", "json file to write the cost breakdown of the best indeling to")
+var reportFileName = flag.String("report", "", "html file to write a report of the best indeling to")
+var geoJSONFileName = flag.String("geojson", "", "geojson file to write the teams and uit wedstrijden of the best indeling to")
+var geocodeCacheFileName = flag.String("geocode-cache", "geocode.cache", "json file with the coordinates of the cities, missing ones are looked up with the APIKEY")
+var calendarFileName = flag.String("calendar", "", "json file with the kalender of the seizoen, required for --ics")
+var icsDirectory = flag.String("ics", "", "directory to write an iCalendar file per team and per vereniging to")
+var objectivesFileName = flag.String("objectives", "", "json file with the weights of the objectives, only the standaard objective by default")
+var paretoFileName = flag.String("pareto", "", "csv file to write the pareto front of indelingen to, instead of optimizing a single cost")
+var paretoPopulation = flag.Int("pareto-population", 100, "number of indelingen in the population of the pareto mode")
+var paretoGenerations = flag.Int("pareto-generations", 2000, "stop the pareto mode at this generation, 0 disables the limit")
+var initializer = flag.String("init", "random", "initial population: random or cluster (regional groepen by k-medoids on the travel distance)")
+var pinsFileName = flag.String("pins", "", "json file with teams pinned to a groep or lot and locked groepen")
+var publishedFileName = flag.String("published", "", "teams file with the published indeling to repair after teams withdrew, were added or moved klasse")
+var changePenalty = flag.Float64("change-penalty", 1.1, "multiplies the cost for every team changing groep compared to the published indeling")
+var alternate = flag.String("alternate", "", "comma separated ids of verenigingen whose teams should alternate thuis and uit, * for all")
+var alternatePenalty = flag.Float64("alternate-penalty", 1.05, "multiplies the cost for every pair of teams of a vereniging playing thuis in the same ronde")
+var unavailableFileName = flag.String("unavailable", "", "json file with the rondes teams or verenigingen can't play thuis")
+var unavailablePenalty = flag.Float64("unavailable-penalty", 1.5, "multiplies the cost for every ronde a team plays thuis while it can't")
+var repeatKilometers = flag.Float64("repeat-km", 0, "cost in km for every pair of teams sharing a groep again after last season, 0 is off, needs --last-season")
+var validateOnly = flag.Bool("validate", false, "only validate the input and print the problems found")
+var wishesFileName = flag.String("wishes", "", "excel (sheet Wensen), csv or json file with the wensen of teams to play samen or niet samen")
+var columnsFileName = flag.String("columns", "", "json file with extra aliases of the column headers")
+var lastSeasonFileName = flag.String("last-season", "", "teams file with the indeling of last season, used as seed and baseline")
+
+var baseline float64
+var coordinaten map[string]Coordinaat
+var kalender *Kalender
+
+//stopReason of the run, written with the seed to every result
+var stopReason string
+
 //A Vector contains byte (=TeamCostID)
 type Vector []TeamCostID
 
 //Evaluate a vector
 func (X Vector) Evaluate() float64 {
+	return X.evaluate(nil)
+}
+
+//evaluate a vector, recording every term in breakdown unless nil
+func (X Vector) evaluate(breakdown *CostBreakdown) float64 {
 	var result float64
+	minimax := optimizer.objectives.Minimax
+
+	var klasseReizen map[Klasse]*KlasseReizen
+	var overschrijding float64
+	if minimax != nil {
+		klasseReizen = make(map[Klasse]*KlasseReizen)
+	}
 
 	for i := 0; i < len(X)/10; i++ {
-		result += float64(optimizer.Evaluate(X[(i * 10):((i + 1) * 10)]).TotalCost)
+		teams := X[(i * 10):((i + 1) * 10)]
+		description := optimizer.descriptions[i*10]
+
+		var groepCost *GroepCost
+		if breakdown != nil {
+			groepCost = new(GroepCost)
+			if description != nil {
+				groepCost.Groep = description.Naam()
+			}
+			breakdown.Groepen = append(breakdown.Groepen, groepCost)
+		}
+
+		travelCosts := optimizer.evaluate(teams, groepCost)
+		result += float64(travelCosts.TotalCost)
+
+		if minimax != nil && description != nil {
+			klasseReizen[description.klasseGroup.klasse] = klasseReizen[description.klasseGroup.klasse].add(teams, travelCosts)
+		}
+	}
+
+	if breakdown != nil {
+		breakdown.GroepenCost = result
+	}
+
+	if minimax != nil {
+		result, overschrijding = minimax.Cost(result, klasseReizen, breakdown)
 	}
 
 	//whish list evaluation
 	//add penalities for not granted whishes
+	for _, wens := range optimizer.bond.wensen {
+		granted := optimizer.WensGranted(X, wens)
+
+		if !granted {
+			result *= wensPenalty
+		}
+
+		if breakdown != nil {
+			multiplier := 1.0
+			if !granted {
+				multiplier = wensPenalty
+			}
+			breakdown.Wensen = append(breakdown.Wensen, WensCost{wens.String(), granted, multiplier})
+		}
+	}
+
+	if optimizer.afwisseling != nil {
+		result *= optimizer.afwisseling.multiplier(X)
+
+		if breakdown != nil {
+			breakdown.ThuisConflicten = optimizer.afwisseling.Conflicten(X)
+		}
+	}
+
+	if optimizer.verhinderd != nil {
+		result *= optimizer.verhinderd.multiplier(X)
+
+		if breakdown != nil {
+			breakdown.Verhinderd = optimizer.verhinderd.Conflicten(X)
+		}
+	}
+
+	if optimizer.herstel != nil {
+		result *= optimizer.herstel.multiplier(X)
+
+		if breakdown != nil {
+			breakdown.Wijzigingen = optimizer.herstel.Wijzigingen(X)
+		}
+	}
+
+	//above the cap of the minimax the indeling is infeasible, whatever its cost
+	if overschrijding > 0 {
+		result = capInfeasible * (1 + overschrijding)
+	}
+
+	if breakdown != nil {
+		breakdown.Cost = result
+	}
 
 	return result
 }
@@ -40,9 +174,19 @@ func (X Vector) Mutate(rng *rand.Rand) {
 		description := optimizer.descriptions[absolutePosition]
 		groupPosition := absolutePosition - description.klasseGroup.begin
 
-		var swapGroupPosition int
-		for swapGroupPosition = groupPosition; swapGroupPosition == groupPosition; {
-			swapGroupPosition = rand.Intn(len(description.klasseGroup.teams))
+		//a position of the klasse to swap with, which may fail when most
+		//teams of the klasse are pinned
+		swapGroupPosition := -1
+		for attempt := 0; attempt < maxSwapAttempts && swapGroupPosition < 0; attempt++ {
+			candidate := rng.Intn(len(description.klasseGroup.teams))
+
+			if candidate != groupPosition && optimizer.Swappable(X, absolutePosition, description.klasseGroup.begin+candidate) {
+				swapGroupPosition = candidate
+			}
+		}
+
+		if swapGroupPosition < 0 {
+			continue
 		}
 
 		groupPosition += description.klasseGroup.begin
@@ -125,6 +269,9 @@ func (X Vector) Crossover(Y gago.Genome, rng *rand.Rand) (gago.Genome, gago.Geno
 		}
 	}
 
+	optimizer.Repair(child1, rng)
+	optimizer.Repair(child2, rng)
+
 	return Vector(child1), Vector(child2)
 }
 
@@ -144,6 +291,8 @@ func MakeVector(rng *rand.Rand) gago.Genome {
 		}
 	}
 
+	optimizer.Repair(vector, rng)
+
 	return Vector(vector)
 }
 
@@ -162,7 +311,7 @@ func truncateString(str string, num int) string {
 
 //PrintDescription info
 func (X Vector) PrintDescription() {
-	log.Print("XXX")
+	log.Printf("Seed %d", *seed)
 	for ix, tid := range X {
 
 		teamInfo := optimizer.matrix.GetTeamInfoByCostID(tid)
@@ -173,21 +322,270 @@ func (X Vector) PrintDescription() {
 	}
 }
 
+//stopOnSignal returns a channel which is closed on the first SIGINT or
+//SIGTERM, a second signal quits immediately
+func stopOnSignal() <-chan struct{} {
+	signals := make(chan os.Signal, 2)
+	stop := make(chan struct{})
+
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+		log.Printf("Received %v, stopping after the current generation", sig)
+		close(stop)
+
+		sig = <-signals
+		log.Printf("Received %v again, quitting", sig)
+		os.Exit(1)
+	}()
+
+	return stop
+}
+
+//writeResults of the best division found
+func writeResults(ga *gago.GA, generation int, reason string) {
+	if *checkpointEvery > 0 {
+		checkpoint := NewCheckpoint(ga, generation, *seed)
+		checkpoint.StopReason = reason
+		checkpoint.Baseline = baseline
+
+		if cerr := checkpoint.Save(*checkpointFileName); cerr != nil {
+			log.Print(cerr)
+		}
+	}
+
+	ga.Best.Genome.(Vector).PrintDescription()
+
+	if *exportXlsxFileName != "" {
+		if err := ga.Best.Genome.(Vector).ExportExcel(*exportXlsxFileName); err != nil {
+			log.Print(err)
+		} else {
+			log.Printf("Exported indeling to %v", *exportXlsxFileName)
+		}
+	}
+
+	if *scheduleCSVFileName != "" {
+		if err := ExportProgrammaCSV(*scheduleCSVFileName, ga.Best.Genome.(Vector).Programma()); err != nil {
+			log.Print(err)
+		}
+	}
+
+	if *scheduleJSONFileName != "" {
+		if err := ExportProgrammaJSON(*scheduleJSONFileName, ga.Best.Genome.(Vector).Programma()); err != nil {
+			log.Print(err)
+		}
+	}
+
+	if optimizer.objectives.Minimax != nil {
+		for _, km := range ga.Best.Genome.(Vector).Explain().Minimax {
+			if len(km.BovenCap) > 0 {
+				log.Printf("Warning: no indeling found within the cap, %d teams of klasse %v travel %.1f %v more than the cap: %v",
+					len(km.BovenCap), km.Klasse, km.Overschrijding, optimizer.objectives.Minimax.Eenheid, strings.Join(km.BovenCap, ", "))
+			}
+		}
+	}
+
+	if optimizer.afwisseling != nil {
+		perVereniging := make(map[string]int)
+		for _, conflict := range optimizer.afwisseling.Conflicten(ga.Best.Genome.(Vector)) {
+			log.Print("Thuis conflict of ", conflict)
+			perVereniging[conflict.Vereniging]++
+		}
+
+		for _, id := range optimizer.afwisseling.verenigingen {
+			log.Printf("Vereniging %v has %d rondes with several teams thuis", id, perVereniging[id])
+		}
+	}
+
+	if optimizer.herhaling != nil {
+		herhalingen := 0
+		for _, groepCost := range ga.Best.Genome.(Vector).Explain().Groepen {
+			if len(groepCost.Herhalingen) > 0 {
+				log.Printf("Groep %v has %d pairs of teams of last season: %v", groepCost.Groep, len(groepCost.Herhalingen), strings.Join(groepCost.Herhalingen, ", "))
+			}
+			herhalingen += len(groepCost.Herhalingen)
+		}
+
+		log.Printf("%d pairs of teams share a groep again", herhalingen)
+	}
+
+	if optimizer.verhinderd != nil {
+		conflicten := optimizer.verhinderd.Conflicten(ga.Best.Genome.(Vector))
+
+		for _, conflict := range conflicten {
+			log.Print("Warning: ", conflict)
+		}
+
+		log.Printf("%d times a team plays thuis in a ronde it can't", len(conflicten))
+	}
+
+	if optimizer.herstel != nil {
+		wijzigingen := optimizer.herstel.Wijzigingen(ga.Best.Genome.(Vector))
+
+		for _, w := range wijzigingen {
+			log.Printf("Team %v moves from groep %v to %v", w.Team, w.Van, w.Naar)
+		}
+
+		log.Printf("%d teams change groep compared to the published indeling", len(wijzigingen))
+	}
+
+	if *explainFileName != "" {
+		if err := ExportCostBreakdownJSON(*explainFileName, ga.Best.Genome.(Vector).Explain()); err != nil {
+			log.Print(err)
+		}
+	}
+
+	if *reportFileName != "" {
+		if err := ga.Best.Genome.(Vector).ExportHTML(*reportFileName); err != nil {
+			log.Print(err)
+		} else {
+			log.Printf("Wrote report to %v", *reportFileName)
+		}
+	}
+
+	if *geoJSONFileName != "" {
+		if err := ga.Best.Genome.(Vector).ExportGeoJSON(*geoJSONFileName, coordinaten); err != nil {
+			log.Print(err)
+		}
+	}
+
+	if kalender != nil {
+		if err := kalender.ExportICS(*icsDirectory, ga.Best.Genome.(Vector).Programma()); err != nil {
+			log.Print(err)
+		}
+	}
+
+	//the baseline is without the repeated opponents, so is the cost it is
+	//compared with
+	if baseline > 0 {
+		optimized := ga.Best.Genome.(Vector).zonderHerhaling()
+		log.Printf("Cost of last season %f, optimized cost %f (%.1f%%)", baseline, optimized, 100.0*(optimized-baseline)/baseline)
+
+		if optimizer.herhaling != nil {
+			log.Printf("Optimized cost with the repeated opponents %f", ga.Best.Fitness)
+		}
+	}
+
+	log.Printf("Result of seed %d, stopped: %v", *seed, stopReason)
+}
+
+//makeVector of the initializer chosen on the command line
+func makeVector() func(rng *rand.Rand) gago.Genome {
+	if *initializer == "cluster" {
+		return MakeClusteredVector
+	}
+	return MakeVector
+}
+
+//runPareto searches the pareto front of indelingen and writes it to the
+//pareto file, every indeling of the front also to an excel file if requested
+func runPareto(seeds []Vector) {
+	rng := rand.New(rand.NewSource(*seed))
+	pareto := NewPareto(*paretoPopulation, rng, makeVector(), seeds...)
+
+	stop := stopOnSignal()
+	stopper := NewStopper(StoppingCriteria{
+		MaxGenerations: *paretoGenerations,
+		MaxDuration:    *maxDuration,
+	})
+	generation := 0
+	reason := ""
+
+	for reason == "" {
+		generation++
+		pareto.Enhance()
+		front := pareto.Front()
+
+		if generation%100 == 0 {
+			log.Printf("Pareto front at generation %d has %d indelingen, least %.0f km", generation, len(front), front[0].Doelen[0])
+		}
+
+		reason = stopper.Check(generation, front[0].Doelen[0])
+
+		select {
+		case <-stop:
+			reason = "interrupted"
+		default:
+		}
+	}
+
+	stopReason = fmt.Sprintf("%s at generation %d", reason, generation)
+	log.Printf("Stopped at generation %d: %s", generation, reason)
+
+	front := pareto.Front()
+
+	for ix, indeling := range front {
+		log.Printf("%d: %.0f km, spreiding %.1f km, %.0f wensen not granted, %d overtredingen",
+			ix+1, indeling.Doelen[0], indeling.Doelen[1], indeling.Doelen[2], indeling.Overtredingen)
+
+		if *exportXlsxFileName != "" {
+			ext := filepath.Ext(*exportXlsxFileName)
+			fileName := strings.TrimSuffix(*exportXlsxFileName, ext) + "-" + strconv.Itoa(ix+1) + ext
+
+			if err := indeling.Vector.ExportExcel(fileName); err != nil {
+				log.Print(err)
+			}
+		}
+	}
+
+	if err := ExportParetoCSV(*paretoFileName, front); err != nil {
+		log.Print(err)
+	} else {
+		log.Printf("Wrote %d indelingen of the pareto front to %v", len(front), *paretoFileName)
+	}
+}
+
 func main() {
 
 	log.Print("Phact Schaakindeling Optimizer v0.1")
-	if len(os.Args) != 5 {
-		log.Fatal("usage: <EXCELSCHEMA> <EXCELTEAMS> <CACHEFILE> <APIKEY>")
+	flag.Parse()
+	if flag.NArg() != 4 {
+		log.Fatal("usage: [options] <SCHEMA> <TEAMS> <CACHEFILE> <APIKEY>")
 		return
 	}
 
-	var excelSchemaFileName = os.Args[1]
-	var excelTeamsFileName = os.Args[2]
-	var distanceCacheFileName = os.Args[3]
-	var googleDistanceMatrixAPIKey = os.Args[4]
+	var schemaFileName = flag.Arg(0)
+	var teamsFileName = flag.Arg(1)
+	var distanceCacheFileName = flag.Arg(2)
+	var googleDistanceMatrixAPIKey = flag.Arg(3)
+
+	var resume *Checkpoint
+
+	if *resumeFileName != "" {
+		var cerr error
+		resume, cerr = LoadCheckpoint(*resumeFileName)
+
+		if cerr != nil {
+			log.Panic(cerr)
+		}
+
+		if *seed != 0 && *seed != resume.Seed {
+			log.Printf("Warning: --seed %d is overridden by seed %d of the checkpoint", *seed, resume.Seed)
+		}
 
-	//0: load excel Schema
-	ss, serr := LoadSpeelSchemaExcel(excelSchemaFileName)
+		*seed = resume.Seed
+		log.Printf("Resuming from generation %d with fitness %f", resume.Generation, resume.Fitness)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	log.Printf("Using seed %d", *seed)
+
+	if *initializer != "random" && *initializer != "cluster" {
+		log.Fatalf("Unknown initializer %v, use random or cluster", *initializer)
+	}
+
+	if *columnsFileName != "" {
+		if cerr := LoadKolomAliases(*columnsFileName); cerr != nil {
+			log.Panic(cerr)
+		}
+	}
+
+	//0: load Schema
+	ss, serr := LoadSpeelSchema(schemaFileName)
 
 	if serr != nil {
 		log.Panic(serr)
@@ -195,14 +593,55 @@ func main() {
 
 	log.Printf("Loaded %d rondes and %d loten", len(ss.Rondes), len(ss.Loten))
 
-	//1: load excel Teams
-	sb, lerr := LoadSchaakbondExcel(excelTeamsFileName)
+	//1: load Teams
+	sb, lerr := LoadSchaakbond(teamsFileName)
 
 	if lerr != nil {
 		log.Panic(lerr)
 	}
 
-	log.Printf("Loaded %d verenigingen and %d teams", len(sb.verenigingen), len(sb.teams))
+	log.Printf("Loaded %d bonden, %d verenigingen and %d teams", len(sb.bonden), len(sb.verenigingen), len(sb.teams))
+
+	//the other sheets of the teams workbook
+	var promovendi []Promovendus
+	var eindstanden []Eindstand
+
+	if strings.ToLower(filepath.Ext(teamsFileName)) == ".xlsx" {
+		var perr error
+		promovendi, perr = LoadPromovendiExcel(teamsFileName)
+
+		if perr != nil {
+			log.Print(perr)
+		}
+
+		var eerr error
+		eindstanden, eerr = LoadEindstandenExcel(teamsFileName)
+
+		if eerr != nil {
+			log.Print(eerr)
+		}
+
+		for _, problem := range CheckGradaties(sb, promovendi, eindstanden) {
+			log.Print("Warning: ", problem)
+		}
+	}
+
+	if *wishesFileName != "" {
+		wensen, werr := LoadWensen(*wishesFileName)
+
+		if werr != nil {
+			log.Fatal(werr)
+		}
+
+		var wensProblems []string
+		sb.wensen, wensProblems = CheckWensen(sb, wensen)
+
+		for _, problem := range wensProblems {
+			log.Print("Warning: ", problem)
+		}
+	}
+
+	log.Printf("Loaded %d promovendi, %d eindstanden and %d wensen", len(promovendi), len(eindstanden), len(sb.wensen))
 
 	if len(sb.teams) > 256 {
 		log.Panic("Currently only a maximum of 256 teams allowed")
@@ -212,6 +651,52 @@ func main() {
 		log.Printf("In Klasse %v are %d teams", klasse, len(teams))
 	}
 
+	//validate before any distance is requested, plaatsen missing from the
+	//cached distances are requested next
+	fouten, waarschuwingen := Validate(sb)
+
+	if *icsDirectory != "" {
+		var kerr error
+		kalender, kerr = LoadKalender(*calendarFileName)
+
+		if kerr == nil {
+			kerr = kalender.Check(sb, ss)
+		}
+
+		if kerr != nil {
+			fouten = append(fouten, fmt.Sprintf("Kalender %v: %v", *calendarFileName, kerr))
+		}
+	}
+
+	cachedInfo, cerr := loadCachedDistances(distanceCacheFileName)
+
+	if cerr != nil && !os.IsNotExist(cerr) {
+		log.Panic(cerr)
+	}
+
+	ontbrekend := ValidateAfstanden(sb, CreateDistanceMatrixWithTravelInformations(cachedInfo))
+
+	for _, waarschuwing := range waarschuwingen {
+		log.Print("Warning: ", waarschuwing)
+	}
+
+	for _, o := range ontbrekend {
+		log.Print("Not cached: ", o)
+	}
+
+	for _, fout := range fouten {
+		log.Print("Error: ", fout)
+	}
+
+	if len(fouten) > 0 {
+		log.Fatalf("Found %d errors in the input, fix them before optimizing", len(fouten))
+	}
+
+	if *validateOnly {
+		log.Printf("Input is valid, %d warnings, %d distances not cached", len(waarschuwingen), len(ontbrekend))
+		return
+	}
+
 	//2: extract unique cities
 	plaatsen := make(map[string]bool)
 
@@ -222,7 +707,7 @@ func main() {
 	uniekePlaatsen := make([]string, 0, len(plaatsen))
 
 	for plaats := range plaatsen {
-		uniekePlaatsen = append(uniekePlaatsen, plaats+", Netherlands")
+		uniekePlaatsen = append(uniekePlaatsen, CityName(plaats))
 	}
 
 	log.Printf("Extracted %d unique city names", len(uniekePlaatsen))
@@ -240,11 +725,32 @@ func main() {
 
 	log.Printf("Loaded %d travel information elements", len(info))
 
+	if *geoJSONFileName != "" {
+		var gerr error
+		coordinaten, gerr = GetCoordinaten(uniekePlaatsen, *geocodeCacheFileName, googleDistanceMatrixAPIKey)
+
+		if gerr != nil {
+			log.Print(gerr)
+		}
+
+		log.Printf("Loaded coordinates of %d cities", len(coordinaten))
+	}
+
 	//4: create a distance matrix and index city names
 	distanceMartix := CreateDistanceMatrixWithTravelInformations(info)
 
 	log.Printf("Created distance matrix for %d cities with %d pairs", len(distanceMartix.citiesByID), len(distanceMartix.citiesTravelInformation))
 
+	afstandFouten := ValidateAfstanden(sb, distanceMartix)
+
+	for _, fout := range afstandFouten {
+		log.Print("Error: ", fout)
+	}
+
+	if len(afstandFouten) > 0 {
+		log.Fatalf("Found %d errors in the distances, fix them before optimizing", len(afstandFouten))
+	}
+
 	//5: create a travel cost matrix for team-pairs and index team ids
 	teamTravelCostMatrix := CreateTeamTravelCostInformationMatrix(sb, distanceMartix)
 
@@ -252,47 +758,140 @@ func main() {
 
 	optimizer = NewOptimizer(teamTravelCostMatrix, ss, sb)
 
-	lastYearGroup1A := []string{"0400691", "0100261", "0400891", "0900611", "0800071", "0400041", "0900081", "0300101", "0900231", "0200541"}
+	if *objectivesFileName != "" {
+		objectives, oerr := LoadObjectives(*objectivesFileName)
+
+		if oerr != nil {
+			log.Panic(oerr)
+		}
+
+		optimizer.objectives = objectives
+	}
+
+	log.Printf("Optimizing %v", optimizer.objectives)
+
+	if *pinsFileName != "" {
+		pins, perr := LoadPins(*pinsFileName)
+
+		if perr == nil {
+			perr = optimizer.Pin(pins)
+		}
 
-	lastYearGroup1ACostIDs, terr := teamTravelCostMatrix.TranslateToTeamCostIDs(lastYearGroup1A)
+		if perr != nil {
+			log.Fatal(perr)
+		}
 
-	if terr != nil {
-		log.Panic(terr)
+		log.Printf("Pinned %d teams to a lot and %d teams to a groep", len(optimizer.lotPins), len(optimizer.groepPins))
 	}
 
-	log.Print(lastYearGroup1A)
-	log.Print(lastYearGroup1ACostIDs)
+	var lastSeason Vector
+	var lastSeasonIndeling *Indeling
 
-	travelCosts := optimizer.Evaluate(lastYearGroup1ACostIDs)
+	if *repeatKilometers > 0 && *lastSeasonFileName == "" {
+		log.Fatal("--repeat-km needs the indeling of last season, see --last-season")
+	}
 
-	log.Print(travelCosts)
-	/*
-		lastYear := []string{
-			"1700401", "1100111", "0900711", "0600171", "1600131", "0200271", "0600081", "1400091", "0600821", "1400431", "0400691", "0100261", "0400891", "0900611", "0800071",
-			"0400041", "0900081", "0300101", "0900231", "0200541", "1200101", "1900331", "0400611", "0800091", "1200071", "1700661", "0400531", "0900411", "0600521", "1100181",
-			"0800431", "0200011", "0600571", "0300371", "0400351", "0200272", "0400532", "0300102", "0300161", "0200542", "0600041", "1100112", "0900712", "0800092", "0800072",
-			"0600311", "0800211", "0900341", "1100291", "0200543", "0900441", "0800261", "0600561", "0400421", "1400511", "1700662", "1100121", "1400261", "0600522", "1400401",
-			"1700402", "1700801", "1700611", "1600051", "1600132", "1900281", "1700241", "1700541", "1900051", "1400101", "0400741", "0100262", "0200151", "0300011", "0100331",
-			"0200321", "0100291", "0100301", "0200221", "0400381", "0400411", "0200141", "0400892", "0800093", "0800031", "0200131", "0900082", "0300103", "0300111", "0300301",
-			"1200102", "1100021", "0100081", "0800094", "0800073", "0400043", "0800381", "0900342", "0600523", "0900621", "0400692", "0900011", "0400612", "0600822", "0800032",
-			"1100091", "0600082", "0800541", "0600602", "0900421", "0900422", "1100113", "1200381", "0800095", "1200231", "0400042", "0400061", "1400092", "0900232", "1400402",
-			"1200271", "1100114", "1400481", "1200441", "1200072", "1100111", "1100221", "1700542", "1400221", "1100182", "1200103", "1400421", "1600011", "1600052", "1600133",
-			"1700091", "1400331", "1400093", "1400071", "1600091", "1700831", "1900332", "0600191", "1700381", "1900211", "1700663", "1700242", "1700141", "1700841", "1900231"}
+	if *lastSeasonFileName != "" {
+		indeling, ierr := LoadIndeling(*lastSeasonFileName)
+
+		if ierr != nil {
+			log.Panic(ierr)
+		}
 
-		lastYearCostIDs, lyerr := optimizer.matrix.TranslateToTeamCostIDs(lastYear)
+		lastSeasonIndeling = indeling
+		lastSeason = optimizer.VectorFromIndeling(indeling)
 
-		if lyerr != nil {
-			panic(lyerr)
+		log.Printf("Loaded %d plaatsingen of last season", len(indeling.plaatsingen))
+	}
+
+	if *alternate != "" {
+		var aerr error
+		optimizer.afwisseling, aerr = optimizer.NewAfwisseling(strings.Split(*alternate, ","), *alternatePenalty)
+
+		if aerr != nil {
+			log.Fatal(aerr)
 		}
 
-		lastYearVector := Vector(lastYearCostIDs)
+		log.Printf("Alternating thuis and uit of the teams of %d verenigingen", len(optimizer.afwisseling.verenigingen))
+	}
+
+	if *unavailableFileName != "" {
+		var verr error
+		optimizer.verhinderd, verr = optimizer.LoadVerhinderingen(*unavailableFileName, *unavailablePenalty)
+
+		if verr != nil {
+			log.Fatal(verr)
+		}
+
+		log.Printf("Loaded the rondes %d teams can't play thuis", optimizer.verhinderd.Count())
+	}
+
+	var published Vector
+
+	if *publishedFileName != "" {
+		indeling, ierr := LoadIndeling(*publishedFileName)
+
+		if ierr != nil {
+			log.Panic(ierr)
+		}
+
+		optimizer.herstel = optimizer.NewHerstel(indeling, *changePenalty)
+		published = optimizer.VectorFromIndeling(indeling)
+
+		log.Printf("Loaded %d plaatsingen of the published indeling", len(indeling.plaatsingen))
+	}
+
+	//the baseline without the repeated opponents, last season shares every
+	//groep again, and the cost per km of the repeated pairs from it
+	if lastSeason != nil {
+		baseline = lastSeason.Evaluate()
+		log.Printf("Cost of the indeling of last season is %f", baseline)
+	}
+
+	if *repeatKilometers > 0 {
+		var herr error
+		optimizer.herhaling, herr = optimizer.NewHerhaling(lastSeasonIndeling, lastSeason, *repeatKilometers)
+
+		if herr != nil {
+			log.Fatal(herr)
+		}
+
+		log.Printf("Every pair of teams sharing a groep again costs %.1f km, %.1f per km", *repeatKilometers, optimizer.herhaling.perKm)
+		log.Printf("With its repeated opponents the indeling of last season costs %f", lastSeason.Evaluate())
+	}
+
+	//costs of the indelingen read, with the optimizer fully configured so
+	//they compare to the cost of the result
+	huidigeIndeling := sb.HuidigeIndeling()
+
+	if len(huidigeIndeling.plaatsingen) > 0 {
+		log.Printf("Cost of the indeling in %v is %f", teamsFileName, optimizer.VectorFromIndeling(huidigeIndeling).Evaluate())
+	}
+
+	if published != nil {
+		log.Printf("With the changes applied the published indeling costs %f", published.Evaluate())
+	}
+
+	if *paretoFileName != "" {
+		var seeds []Vector
+		if published != nil {
+			seeds = append(seeds, published)
+		}
+		if lastSeason != nil {
+			seeds = append(seeds, lastSeason)
+		}
 
-		e := lastYearVector.Evaluate()
+		runPareto(seeds)
+		return
+	}
 
-		log.Print("Fitness lastYear", e)
-	*/
-	// open output file
-	fo, err := os.Create("fitness.txt")
+	// open output file, a resumed run appends to it
+	var fo *os.File
+	if resume != nil {
+		fo, err = os.OpenFile("fitness.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	} else {
+		fo, err = os.Create("fitness.txt")
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -303,23 +902,124 @@ func main() {
 		}
 	}()
 
-	var ga = gago.Generational(MakeVector)
+	fo.WriteString("# seed " + strconv.FormatInt(*seed, 10) + "\n")
+
+	firstGeneration := 1
+
+	var ga = gago.Generational(makeVector())
+	if resume != nil {
+		//the state of the generator can't be stored, so continue with a
+		//derived seed which keeps a resumed run reproducible
+		ga.RNG = rand.New(rand.NewSource(*seed + int64(resume.Generation)))
+	} else {
+		ga.RNG = rand.New(rand.NewSource(*seed))
+	}
 	ga.Initialize()
 
+	//the inputs and settings of the problem, a resumed run must solve the same
+	var perr error
+	runParameters, perr = RunParameters(map[string]string{
+		"schema":      schemaFileName,
+		"teams":       teamsFileName,
+		"wishes":      *wishesFileName,
+		"columns":     *columnsFileName,
+		"objectives":  *objectivesFileName,
+		"pins":        *pinsFileName,
+		"published":   *publishedFileName,
+		"last-season": *lastSeasonFileName,
+		"unavailable": *unavailableFileName,
+	}, map[string]string{
+		"gewichten":           optimizer.objectives.String(),
+		"change-penalty":      flag.Lookup("change-penalty").Value.String(),
+		"alternate":           *alternate,
+		"alternate-penalty":   flag.Lookup("alternate-penalty").Value.String(),
+		"unavailable-penalty": flag.Lookup("unavailable-penalty").Value.String(),
+		"repeat-km":           flag.Lookup("repeat-km").Value.String(),
+		"init":                *initializer,
+		"populations":         fmt.Sprintf("%d×%d", len(ga.Populations), len(ga.Populations[0].Individuals)),
+	})
+
+	if perr != nil {
+		log.Panic(perr)
+	}
+
+	if resume != nil {
+		if len(resume.Parameters) == 0 {
+			log.Printf("Warning: checkpoint %v has no parameters, the inputs are not checked", *resumeFileName)
+		} else if cerr := resume.CheckParameters(runParameters); cerr != nil {
+			log.Fatal(cerr)
+		}
+
+		if rerr := resume.Restore(&ga); rerr != nil {
+			log.Panic(rerr)
+		}
+
+		firstGeneration = resume.Generation + 1
+	} else if *initialFileName != "" {
+		initial, ierr := LoadCheckpoint(*initialFileName)
+
+		if ierr != nil {
+			log.Panic(ierr)
+		}
+
+		if ierr = initial.InsertBest(&ga); ierr != nil {
+			log.Panic(ierr)
+		}
+
+		log.Printf("Seeded run with division of %v with fitness %f", *initialFileName, ga.Best.Fitness)
+	} else if published != nil {
+		InsertVector(&ga, published)
+
+		log.Printf("Seeded run with the published indeling")
+	} else if lastSeason != nil {
+		InsertVector(&ga, lastSeason)
+
+		log.Printf("Seeded run with the indeling of last season")
+	}
+
+	stop := stopOnSignal()
+	stopper := NewStopper(StoppingCriteria{
+		MaxGenerations: *maxGenerations,
+		MaxDuration:    *maxDuration,
+		MaxStagnation:  *maxStagnation,
+		TargetCost:     *targetCost,
+	})
+	generation := firstGeneration - 1
+	reason := ""
+
 	var lastFitness float64
-	for i := 1; i < 5000000; i++ {
+	for reason == "" {
+		generation++
 		ga.Enhance()
 
-		if i%1000 == 0 {
+		if generation%1000 == 0 {
 			fo.WriteString(strconv.FormatFloat(ga.Best.Fitness, 'f', 6, 64) + "\n")
-			fmt.Printf("Best fitness at generation %d: %f (%v)\n", i, ga.Best.Fitness, ga.Best.Fitness-lastFitness)
+			fmt.Printf("Best fitness at generation %d: %f (%v) seed %d\n", generation, ga.Best.Fitness, ga.Best.Fitness-lastFitness, *seed)
 			ga.Best.Genome.(Vector).PrintDescription()
 			lastFitness = ga.Best.Fitness
 		}
+
+		if *checkpointEvery > 0 && generation%*checkpointEvery == 0 {
+			if cerr := NewCheckpoint(&ga, generation, *seed).Save(*checkpointFileName); cerr != nil {
+				log.Print(cerr)
+			}
+		}
+
+		reason = stopper.Check(generation, ga.Best.Fitness)
+
+		select {
+		case <-stop:
+			reason = "interrupted"
+		default:
+		}
 	}
+
+	stopReason = fmt.Sprintf("%s at generation %d", reason, generation)
+	log.Printf("Stopped at generation %d: %s", generation, reason)
 	fo.WriteString(strconv.FormatFloat(ga.Best.Fitness, 'f', 6, 64) + "\n")
+	fo.Sync()
 	fmt.Print(ga.Best)
 
-	ga.Best.Genome.(Vector).PrintDescription()
+	writeResults(&ga, generation, reason)
 
 }
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+)
+
+//objectiveFunc cost of a groep from the reis of every team
+type objectiveFunc func(objectives *Objectives, reizen []Reis) float64
+
+//objectiveFuncs by naam, the unit of every objective is given in its comment
+var objectiveFuncs = map[string]objectiveFunc{
+	//standaard mean travel over all rondes weighted by the spread of the uit
+	//wedstrijden, summed over the teams
+	"standaard": func(objectives *Objectives, reizen []Reis) float64 {
+		var cost uint64
+		for _, reis := range reizen {
+			cost += reis.Cost()
+		}
+		return float64(cost)
+	},
+	//kilometers travelled by all teams
+	"kilometers": func(objectives *Objectives, reizen []Reis) float64 {
+		var meters uint64
+		for _, reis := range reizen {
+			meters += reis.TotalDistance
+		}
+		return float64(meters) / 1000.0
+	},
+	//uren travelled by all teams
+	"uren": func(objectives *Objectives, reizen []Reis) float64 {
+		var seconds uint64
+		for _, reis := range reizen {
+			seconds += reis.TotalDuration
+		}
+		return float64(seconds) / 3600.0
+	},
+	//langste single trip in km
+	"langste": func(objectives *Objectives, reizen []Reis) float64 {
+		var meters uint64
+		for _, reis := range reizen {
+			if reis.LongestDistance > meters {
+				meters = reis.LongestDistance
+			}
+		}
+		return float64(meters) / 1000.0
+	},
+	//kwadraten sum of the squared trips in km²
+	"kwadraten": func(objectives *Objectives, reizen []Reis) float64 {
+		var squares float64
+		for _, reis := range reizen {
+			squares += reis.SquaredDistance
+		}
+		return squares / 1000000.0
+	},
+	//eerlijkheid km of the team travelling most minus the team travelling least
+	"eerlijkheid": func(objectives *Objectives, reizen []Reis) float64 {
+		most, least := uint64(0), uint64(math.MaxUint64)
+		for _, reis := range reizen {
+			if reis.TotalDistance > most {
+				most = reis.TotalDistance
+			}
+			if reis.TotalDistance < least {
+				least = reis.TotalDistance
+			}
+		}
+		return float64(most-least) / 1000.0
+	},
+	//co2 estimate in kg of all teams
+	"co2": func(objectives *Objectives, reizen []Reis) float64 {
+		var meters uint64
+		for _, reis := range reizen {
+			meters += reis.TotalDistance
+		}
+		return float64(meters) / 1000.0 * objectives.CO2PerKm
+	},
+}
+
+//Objectives weighted into the cost of a groep
+type Objectives struct {
+	//Gewichten of the objectives by naam
+	Gewichten map[string]float64 `json:"gewichten"`
+	//CO2PerKm kg of co2 per km a team travels, by default 2 cars of 0.12 kg
+	CO2PerKm float64 `json:"co2PerKm"`
+	//Minimax of the travel of a team within a klasse, added to the cost of
+	//the groepen
+	Minimax *Minimax `json:"minimax"`
+
+	namen []string
+}
+
+//DefaultObjectives only the standaard objective, the cost used before
+//objectives were configurable
+func DefaultObjectives() *Objectives {
+	objectives := new(Objectives)
+	objectives.Gewichten = map[string]float64{"standaard": 1}
+	objectives.CO2PerKm = 0.24
+	objectives.namen = []string{"standaard"}
+	return objectives
+}
+
+//LoadObjectives from a json file, e.g. { "gewichten": { "kilometers": 1, "eerlijkheid": 2 } }
+func LoadObjectives(fileName string) (*Objectives, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objectives := new(Objectives)
+	objectives.CO2PerKm = 0.24
+	err = json.Unmarshal(file, objectives)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if objectives.CO2PerKm < 0 {
+		return nil, fmt.Errorf("Negative co2PerKm %v in %v", objectives.CO2PerKm, fileName)
+	}
+
+	if len(objectives.Gewichten) == 0 && objectives.Minimax == nil {
+		return nil, fmt.Errorf("No objectives in %v", fileName)
+	}
+
+	if minimax := objectives.Minimax; minimax != nil {
+		if minimax.Percentiel == 0 {
+			minimax.Percentiel = 100
+		}
+
+		if minimax.Eenheid == "" {
+			minimax.Eenheid = "km"
+		}
+
+		if minimax.Percentiel < 0 || minimax.Percentiel > 100 {
+			return nil, fmt.Errorf("Minimax percentiel %v in %v is not between 0 and 100", minimax.Percentiel, fileName)
+		}
+
+		if minimax.Eenheid != "km" && minimax.Eenheid != "uren" {
+			return nil, fmt.Errorf("Minimax eenheid %v in %v is not km or uren", minimax.Eenheid, fileName)
+		}
+
+		if minimax.Gewicht < 0 || minimax.Cap < 0 {
+			return nil, fmt.Errorf("Negative minimax gewicht %v or cap %v in %v", minimax.Gewicht, minimax.Cap, fileName)
+		}
+	}
+
+	for naam, gewicht := range objectives.Gewichten {
+		if _, ok := objectiveFuncs[naam]; !ok {
+			return nil, fmt.Errorf("Unknown objective %v in %v, known are %v", naam, fileName, strings.Join(ObjectiveNamen(), ", "))
+		}
+
+		if gewicht < 0 {
+			return nil, fmt.Errorf("Negative gewicht %v of objective %v in %v", gewicht, naam, fileName)
+		}
+		objectives.namen = append(objectives.namen, naam)
+	}
+
+	//a fixed order, so the sum is the same in every run
+	sort.Strings(objectives.namen)
+
+	return objectives, nil
+}
+
+//ObjectiveNamen of every known objective
+func ObjectiveNamen() []string {
+	namen := make([]string, 0, len(objectiveFuncs))
+	for naam := range objectiveFuncs {
+		namen = append(namen, naam)
+	}
+	sort.Strings(namen)
+	return namen
+}
+
+//Cost of a groep, the weighted sum of the objectives. The weighted terms
+//are recorded in terms unless nil
+func (objectives *Objectives) Cost(reizen []Reis, terms map[string]float64) uint64 {
+	var cost float64
+
+	for _, naam := range objectives.namen {
+		term := objectives.Gewichten[naam] * objectiveFuncs[naam](objectives, reizen)
+
+		if terms != nil {
+			terms[naam] = term
+		}
+
+		cost += term
+	}
+
+	return uint64(cost)
+}
+
+//String of the objectives with their weights
+func (objectives *Objectives) String() string {
+	parts := make([]string, len(objectives.namen))
+	for ix, naam := range objectives.namen {
+		parts[ix] = fmt.Sprintf("%v×%v", objectives.Gewichten[naam], naam)
+	}
+
+	if minimax := objectives.Minimax; minimax != nil {
+		parts = append(parts, fmt.Sprintf("%v×minimax (percentiel %v of the %v of a team per klasse)", minimax.Gewicht, minimax.Percentiel, minimax.Eenheid))
+
+		if minimax.Cap > 0 {
+			parts = append(parts, fmt.Sprintf("cap of %v %v", minimax.Cap, minimax.Eenheid))
+		}
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+//capInfeasible is the cost of an indeling with teams travelling more than
+//the cap, times 1 plus the travel above the cap: the cap is a hard constraint,
+//every indeling above it is worse than any indeling below it and ranked by
+//how far it is above the cap, see Vector.evaluate
+const capInfeasible = 1e100
+
+//Minimax of the travel of a team over the seizoen within a klasse, so the
+//teams at the edges of the country don't get all the longest trips
+type Minimax struct {
+	//Gewicht of the travel in the eenheid against the cost of the groepen,
+	//which is in the unit of the objectives, see the README
+	Gewicht float64 `json:"gewicht"`
+	//Percentiel of the teams of a klasse, 100 (default) is the team travelling most
+	Percentiel float64 `json:"percentiel"`
+	//Eenheid of the travel, km (default) or uren
+	Eenheid string `json:"eenheid"`
+	//Cap on the travel of every team, 0 is no cap. A hard constraint, see capInfeasible
+	Cap float64 `json:"cap"`
+}
+
+//KlasseReizen of the teams of the groepen of a klasse
+type KlasseReizen struct {
+	teams  []TeamCostID
+	reizen []Reis
+	//penalties of the groep of every team
+	penalties []float64
+}
+
+//add the teams of a groep to the klasse, creating it when nil
+func (klasseReizen *KlasseReizen) add(teams []TeamCostID, travelCosts *TravelCosts) *KlasseReizen {
+	if klasseReizen == nil {
+		klasseReizen = new(KlasseReizen)
+	}
+
+	klasseReizen.teams = append(klasseReizen.teams, teams...)
+	klasseReizen.reizen = append(klasseReizen.reizen, travelCosts.Reizen...)
+
+	for range teams {
+		klasseReizen.penalties = append(klasseReizen.penalties, travelCosts.Penalty)
+	}
+
+	return klasseReizen
+}
+
+//travel of a reis in the eenheid
+func (minimax *Minimax) travel(reis *Reis) float64 {
+	if minimax.Eenheid == "uren" {
+		return float64(reis.TotalDuration) / 3600.0
+	}
+	return float64(reis.TotalDistance) / 1000.0
+}
+
+//Cost of the groepen with the minimax term of every klasse added, multiplied
+//by the penalty of the groep of the team at the percentiel so it keeps its
+//weight, and the travel of the teams above the cap summed over the teams. The
+//terms are recorded in breakdown unless nil
+func (minimax *Minimax) Cost(cost float64, klasseReizen map[Klasse]*KlasseReizen, breakdown *CostBreakdown) (float64, float64) {
+	var overschrijding float64
+
+	for k := Meester; k <= Derde; k++ {
+		kr := klasseReizen[k]
+
+		if kr == nil {
+			continue
+		}
+
+		travels := make([]float64, len(kr.reizen))
+		order := make([]int, len(kr.reizen))
+		var bovenCap []string
+		var klasseOverschrijding float64
+
+		for ix := range kr.reizen {
+			travels[ix] = minimax.travel(&kr.reizen[ix])
+			order[ix] = ix
+
+			if minimax.Cap > 0 && travels[ix] > minimax.Cap {
+				klasseOverschrijding += travels[ix] - minimax.Cap
+				bovenCap = append(bovenCap, optimizer.matrix.GetTeamInfoByCostID(kr.teams[ix]).team.id)
+			}
+		}
+
+		sort.SliceStable(order, func(a, b int) bool { return travels[order[a]] < travels[order[b]] })
+
+		ix := int(math.Ceil(minimax.Percentiel/100.0*float64(len(travels)))) - 1
+		if ix < 0 {
+			ix = 0
+		}
+
+		waarde := travels[order[ix]]
+		term := minimax.Gewicht * waarde * kr.penalties[order[ix]]
+		cost += term
+
+		if breakdown != nil {
+			breakdown.Minimax = append(breakdown.Minimax, KlasseMinimax{k.String(), waarde, term, bovenCap, klasseOverschrijding})
+		}
+
+		overschrijding += klasseOverschrijding
+	}
+
+	return cost, overschrijding
+}
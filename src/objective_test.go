@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+func TestMinimaxCost(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	//klasseReizen of 2 groepen of the Eerste klasse with km per team and the
+	//penalty of each groep
+	klasseReizen := func(km [2][]uint64, penalties [2]float64) map[Klasse]*KlasseReizen {
+		var kr *KlasseReizen
+
+		for g := range km {
+			travelCosts := new(TravelCosts)
+			travelCosts.Penalty = penalties[g]
+
+			for _, k := range km[g] {
+				travelCosts.Reizen = append(travelCosts.Reizen, Reis{TotalDistance: k * 1000})
+			}
+
+			kr = kr.add(X[10+10*g:10+10*g+len(km[g])], travelCosts)
+		}
+
+		return map[Klasse]*KlasseReizen{Eerste: kr}
+	}
+
+	tests := []struct {
+		naam      string
+		minimax   Minimax
+		km        [2][]uint64
+		penalties [2]float64
+		cost      float64
+		//overschrijding of the cap
+		overschrijding float64
+	}{
+		{"most travelling team", Minimax{Gewicht: 2, Percentiel: 100, Eenheid: "km"}, [2][]uint64{{10, 30}, {20}}, [2]float64{1, 1}, 100 + 60, 0},
+		{"penalty of its own groep", Minimax{Gewicht: 2, Percentiel: 100, Eenheid: "km"}, [2][]uint64{{10, 30}, {20}}, [2]float64{1, pdPenalty}, 100 + 60, 0},
+		{"penalized groep", Minimax{Gewicht: 2, Percentiel: 100, Eenheid: "km"}, [2][]uint64{{10, 30}, {20}}, [2]float64{pdPenalty, verenigingPenalty}, 100 + 60*pdPenalty, 0},
+		{"percentiel", Minimax{Gewicht: 1, Percentiel: 50, Eenheid: "km"}, [2][]uint64{{10, 40}, {30, 20}}, [2]float64{1, 3}, 100 + 20*3, 0},
+		{"below the cap", Minimax{Gewicht: 1, Percentiel: 100, Eenheid: "km", Cap: 30}, [2][]uint64{{10, 30}, {20}}, [2]float64{1, 1}, 100 + 30, 0},
+		{"above the cap", Minimax{Gewicht: 1, Percentiel: 100, Eenheid: "km", Cap: 15}, [2][]uint64{{10, 30}, {20}}, [2]float64{1, 1}, 100 + 30, 15 + 5},
+	}
+
+	for _, test := range tests {
+		breakdown := new(CostBreakdown)
+		cost, overschrijding := test.minimax.Cost(100, klasseReizen(test.km, test.penalties), breakdown)
+
+		if cost != test.cost || overschrijding != test.overschrijding {
+			t.Errorf("%v: cost %v above the cap %v, expected %v and %v", test.naam, cost, overschrijding, test.cost, test.overschrijding)
+		}
+
+		if len(breakdown.Minimax) != 1 {
+			t.Errorf("%v: %d klasses in the breakdown", test.naam, len(breakdown.Minimax))
+		}
+	}
+}
+
+func TestMinimaxCap(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	optimizer.objectives.Minimax = &Minimax{Gewicht: 1, Percentiel: 100, Eenheid: "km"}
+	binnen := X.Evaluate()
+
+	//the cap below the travel of some teams makes the indeling infeasible,
+	//ranked by the travel above the cap
+	var kosten []float64
+	for _, c := range []float64{1e9, 1, 0.5} {
+		optimizer.objectives.Minimax.Cap = c
+		kosten = append(kosten, X.Evaluate())
+	}
+
+	if kosten[0] != binnen {
+		t.Errorf("Cost %v within the cap, expected %v", kosten[0], binnen)
+	}
+
+	if kosten[1] < capInfeasible || kosten[1] <= binnen || kosten[2] <= kosten[1] {
+		t.Errorf("Costs %v above the cap not infeasible and ranked", kosten[1:])
+	}
+
+	if indeling := NewParetoIndeling(X); indeling.Overtredingen < len(X) {
+		t.Errorf("%d overtredingen, expected every team above the cap", indeling.Overtredingen)
+	}
+}
+
+func TestLoadObjectivesNegative(t *testing.T) {
+	tests := []struct {
+		naam    string
+		content string
+		fout    bool
+	}{
+		{"gewichten", `{ "gewichten": { "kilometers": 1, "eerlijkheid": 2 } }`, false},
+		{"negative gewicht", `{ "gewichten": { "kilometers": -1 } }`, true},
+		{"negative minimax gewicht", `{ "minimax": { "gewicht": -1 } }`, true},
+		{"negative cap", `{ "minimax": { "gewicht": 1, "cap": -8 } }`, true},
+		{"negative co2PerKm", `{ "gewichten": { "co2": 1 }, "co2PerKm": -0.1 }`, true},
+	}
+
+	for _, test := range tests {
+		fileName, remove := writeTestFile(t, "objectives.json", test.content)
+		_, err := LoadObjectives(fileName)
+		remove()
+
+		if (err != nil) != test.fout {
+			t.Errorf("%v: error %v", test.naam, err)
+		}
+	}
+}
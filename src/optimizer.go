@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sort"
 )
 
 //TeamCostID identiefier
@@ -116,15 +117,24 @@ func CreateTeamTravelCostInformationMatrix(sb *Schaakbond, distanceMatrix *Dista
 	matrix.teamCostMatrix = make(map[TeamCostPairID]*TravelInformation)
 	matrix.teamInfoByCostID = make(map[TeamCostID]*TeamInfo)
 
-	for _, fromTeam := range sb.teams {
-		for _, toTeam := range sb.teams {
+	//sort team ids so every run hands out the same TeamCostIDs
+	teamIDs := make([]string, 0, len(sb.teams))
+	for id := range sb.teams {
+		teamIDs = append(teamIDs, id)
+	}
+	sort.Strings(teamIDs)
+
+	for _, fromTeamID := range teamIDs {
+		fromTeam := sb.teams[fromTeamID]
+		for _, toTeamID := range teamIDs {
+			toTeam := sb.teams[toTeamID]
 			if fromTeam.id < toTeam.id {
 
 				fromTeamInfo := matrix.GetOrAddTeamCostInfoByTeam(fromTeam)
 				toTeamInfo := matrix.GetOrAddTeamCostInfoByTeam(toTeam)
 
-				fromTeamCity := distanceMatrix.GetCityByName(fromTeam.vereniging.plaats + ", Netherlands")
-				toTeamCity := distanceMatrix.GetCityByName(toTeam.vereniging.plaats + ", Netherlands")
+				fromTeamCity := distanceMatrix.GetCityByName(CityName(fromTeam.vereniging.plaats))
+				toTeamCity := distanceMatrix.GetCityByName(CityName(toTeam.vereniging.plaats))
 
 				var info *TravelInformation
 				if fromTeamCity.ID == toTeamCity.ID {
@@ -145,9 +155,41 @@ func CreateTeamTravelCostInformationMatrix(sb *Schaakbond, distanceMatrix *Dista
 	return matrix
 }
 
+//wensPenalty multiplies the cost for every wens not granted
+const wensPenalty = 1.2
+
+//positionOf a team in the teams, -1 if not present
+func positionOf(teams []TeamCostID, teamID TeamCostID) int {
+	for ix, tid := range teams {
+		if tid == teamID {
+			return ix
+		}
+	}
+	return -1
+}
+
+//WensGranted checks a wens against a complete vector of teams
+func (optimizer *Optimizer) WensGranted(teams []TeamCostID, wens Wens) bool {
+	a := positionOf(teams, optimizer.matrix.GetTeamCostID(wens.teamID))
+	b := positionOf(teams, optimizer.matrix.GetTeamCostID(wens.anderTeamID))
+
+	samen := a >= 0 && b >= 0 &&
+		optimizer.descriptions[a] != nil &&
+		optimizer.descriptions[a] == optimizer.descriptions[b]
+
+	if wens.soort == NietSamen {
+		return !samen
+	}
+	return samen
+}
+
 //TravelCosts info
 type TravelCosts struct {
 	TotalDuration, TotalDistance, TotalCost uint64
+	//Penalty product of the multipliers of the penalties that fired
+	Penalty float64
+	//Reizen of the teams by lot
+	Reizen []Reis
 }
 
 //KlasseGroup info
@@ -164,12 +206,35 @@ type Description struct {
 	begin, end  int
 }
 
+//Naam of the groep, e.g. M or 1A
+func (description *Description) Naam() string {
+	klasse := description.klasseGroup.klasse.String()
+
+	if klasse == "M" && len(description.klasseGroup.teams) <= GroepGrootte {
+		return klasse
+	}
+
+	return klasse + string(rune('A'+description.groupNr))
+}
+
 //Optimizer info
 type Optimizer struct {
 	matrix       *TeamCostMatrix
 	schema       *SpeelSchema
 	bond         *Schaakbond
 	descriptions []*Description
+	klasseGroups [Derde + 1]*KlasseGroup
+	objectives   *Objectives
+	herstel      *Herstel
+	afwisseling  *Afwisseling
+	verhinderd   *Verhinderingen
+	herhaling    *Herhaling
+
+	//fixed positions of the teams pinned to a lot
+	fixed       []bool
+	lotPins     []lotPin
+	groepPins   []groepPin
+	pinnedGroep map[TeamCostID]*Description
 }
 
 //NewOptimizer create a optimizer
@@ -178,8 +243,11 @@ func NewOptimizer(matrix *TeamCostMatrix, schema *SpeelSchema, bond *Schaakbond)
 	optimizer.matrix = matrix
 	optimizer.schema = schema
 	optimizer.bond = bond
+	optimizer.objectives = DefaultObjectives()
 
 	optimizer.descriptions = make([]*Description, len(bond.teams), len(bond.teams))
+	optimizer.fixed = make([]bool, len(bond.teams), len(bond.teams))
+	optimizer.pinnedGroep = make(map[TeamCostID]*Description)
 
 	ix := 0
 
@@ -199,6 +267,7 @@ func NewOptimizer(matrix *TeamCostMatrix, schema *SpeelSchema, bond *Schaakbond)
 		klasseGroup.begin = ix
 		klasseGroup.end = ix + (len(teams) - 1)
 		klasseGroup.klasse = k
+		optimizer.klasseGroups[k] = klasseGroup
 
 		for i := 0; i < len(teams)/10; i++ {
 			description := new(Description)
@@ -218,8 +287,117 @@ func NewOptimizer(matrix *TeamCostMatrix, schema *SpeelSchema, bond *Schaakbond)
 	return optimizer
 }
 
+//Reis statistics of the uit wedstrijden of a team in a groep
+type Reis struct {
+	TotalDistance   uint64  `json:"totalDistance"`
+	TotalDuration   uint64  `json:"totalDuration"`
+	LongestDistance uint64  `json:"longestDistance"`
+	LongestDuration uint64  `json:"longestDuration"`
+	MeanAllDistance float64 `json:"meanAllDistance"`
+	MeanAllDuration float64 `json:"meanAllDuration"`
+	MeanUitDistance float64 `json:"meanUitDistance"`
+	MeanUitDuration float64 `json:"meanUitDuration"`
+	SdUitDistance   float64 `json:"sdUitDistance"`
+	SdUitDuration   float64 `json:"sdUitDuration"`
+	SquaredDistance float64 `json:"squaredDistance"`
+	UitCount        int     `json:"uitCount"`
+}
+
+//Cost of the reis, the mean travel over all rondes weighted by the spread of
+//the uit wedstrijden
+func (reis *Reis) Cost() uint64 {
+	return uint64((reis.MeanAllDistance * reis.SdUitDistance) + (reis.MeanAllDuration * reis.SdUitDuration))
+}
+
+//TeamReis of the team at lotNR of a groep
+func (optimizer *Optimizer) TeamReis(teams []TeamCostID, lotNR int) Reis {
+	var reis Reis
+	teamID := teams[lotNR]
+
+	travelInfos := make([]*TravelInformation, 0, 9)
+
+	for ronde := 0; ronde < 9; ronde++ {
+		//ronde 9 is on central location, for Meester klasse
+		if ronde == 8 {
+			teamInfo := optimizer.matrix.GetTeamInfoByCostID(teamID)
+
+			if teamInfo != nil &&
+				teamInfo.team.klasse == Meester {
+				//special
+			}
+		}
+
+		if optimizer.schema.Loten[lotNR].Rondes[ronde].Verplaatsing == Uit {
+			travelInfo := optimizer.matrix.GetTeamsTravelCost(teamID, teams[optimizer.schema.Loten[lotNR].Rondes[ronde].Tegenstander])
+
+			if travelInfo == nil {
+				log.Panic("Unknown travelcosts for ", teamID, " <-> ", teams[optimizer.schema.Loten[lotNR].Rondes[ronde].Tegenstander])
+			}
+
+			travelInfos = append(travelInfos, travelInfo)
+			reis.TotalDistance += travelInfo.Distance
+			reis.TotalDuration += travelInfo.Duration
+			reis.SquaredDistance += math.Pow(float64(travelInfo.Distance), 2)
+
+			if travelInfo.Distance > reis.LongestDistance {
+				reis.LongestDistance = travelInfo.Distance
+			}
+
+			if travelInfo.Duration > reis.LongestDuration {
+				reis.LongestDuration = travelInfo.Duration
+			}
+		}
+	}
+
+	reis.UitCount = len(travelInfos)
+	reis.MeanAllDistance = float64(reis.TotalDistance) / 8.0
+	reis.MeanAllDuration = float64(reis.TotalDuration) / 8.0
+	reis.MeanUitDistance = float64(reis.TotalDistance) / float64(len(travelInfos))
+	reis.MeanUitDuration = float64(reis.TotalDuration) / float64(len(travelInfos))
+
+	for _, ti := range travelInfos {
+		reis.SdUitDistance += math.Pow(float64(ti.Distance)-reis.MeanUitDistance, 2)
+		reis.SdUitDuration += math.Pow(float64(ti.Duration)-reis.MeanUitDuration, 2)
+	}
+
+	reis.SdUitDistance = math.Sqrt(reis.SdUitDistance / float64(len(travelInfos)-1))
+	reis.SdUitDuration = math.Sqrt(reis.SdUitDuration / float64(len(travelInfos)-1))
+
+	return reis
+}
+
+//containsString value in values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//pdPenalty multiplies the cost of a groep without 2 promovendi or kampioenen
+//and 1 degradant
+const pdPenalty = 1.9
+
+//verenigingPenalty multiplies the cost of a groep in which a vereniging has
+//more than one team
+const verenigingPenalty = 2.5
+
 //Evaluate cost of team loten
 func (optimizer *Optimizer) Evaluate(teams []TeamCostID) *TravelCosts {
+	return optimizer.evaluate(teams, nil)
+}
+
+//Explain the cost of team loten, term by term
+func (optimizer *Optimizer) Explain(teams []TeamCostID) *GroepCost {
+	groepCost := new(GroepCost)
+	optimizer.evaluate(teams, groepCost)
+	return groepCost
+}
+
+//evaluate cost of team loten, recording every term in groepCost unless nil
+func (optimizer *Optimizer) evaluate(teams []TeamCostID, groepCost *GroepCost) *TravelCosts {
 
 	result := new(TravelCosts)
 
@@ -231,14 +409,13 @@ func (optimizer *Optimizer) Evaluate(teams []TeamCostID) *TravelCosts {
 	promovendi = 0
 	degradanten = 0
 	kampioenen = 0
-	verenigingen := make(map[string]int)
+	//distinct verenigingen of the groep, without a map so evaluating doesn't allocate it
+	var verenigingIDs [GroepGrootte]string
+	verenigingen := 0
+	//one allocation for the reizen of the groep, they are kept by the minimax
+	reizen := make([]Reis, len(teams))
 
 	for lotNR, teamID := range teams {
-		var totalDuration, totalDistance uint64
-
-		travelInfos := make([]*TravelInformation, 0, 9)
-		uitCount := 0
-
 		teamInfo := optimizer.matrix.GetTeamInfoByCostID(teamID)
 
 		if teamInfo == nil {
@@ -257,73 +434,80 @@ func (optimizer *Optimizer) Evaluate(teams []TeamCostID) *TravelCosts {
 			break
 		}
 
-		vercount := verenigingen[teamInfo.team.vereniging.id]
-		verenigingen[teamInfo.team.vereniging.id] = (vercount + 1)
-
-		for ronde := 0; ronde < 9; ronde++ {
-			//ronde 9 is on central location, for Meester klasse
-			if ronde == 8 {
-				teamInfo := optimizer.matrix.GetTeamInfoByCostID(teamID)
-
-				if teamInfo != nil &&
-					teamInfo.team.klasse == Meester {
-					//special
-				}
-			}
+		if !containsString(verenigingIDs[:verenigingen], teamInfo.team.vereniging.id) && verenigingen < len(verenigingIDs) {
+			verenigingIDs[verenigingen] = teamInfo.team.vereniging.id
+			verenigingen++
+		}
 
-			if optimizer.schema.Loten[lotNR].Rondes[ronde].Verplaatsing == Uit {
-				travelInfo := optimizer.matrix.GetTeamsTravelCost(teamID, teams[optimizer.schema.Loten[lotNR].Rondes[ronde].Tegenstander])
+		reizen[lotNR] = optimizer.TeamReis(teams, lotNR)
+		reis := &reizen[lotNR]
 
-				if travelInfo == nil {
-					log.Panic("Unknown travelcosts for ", teamID, " <-> ", teams[optimizer.schema.Loten[lotNR].Rondes[ronde].Tegenstander])
-				}
+		result.TotalDistance += reis.TotalDistance
+		result.TotalDuration += reis.TotalDuration
 
-				travelInfos = append(travelInfos, travelInfo)
-				totalDistance += travelInfo.Distance
-				totalDuration += travelInfo.Duration
-				uitCount++
-			}
+		if groepCost != nil {
+			groepCost.Teams = append(groepCost.Teams, TeamCost{teamInfo.team.id, teamInfo.team.naam, lotNR + 1, reis, reis.Cost()})
 		}
+	}
 
-		meanAllDistance := float64(totalDistance) / 8.0
-		meanAllDuration := float64(totalDuration) / 8.0
-		meanUitDistance := float64(totalDistance) / float64(len(travelInfos))
-		meanUitDuration := float64(totalDuration) / float64(len(travelInfos))
+	var terms map[string]float64
+	if groepCost != nil {
+		terms = make(map[string]float64)
+	}
 
-		sdUitDistance := 0.0
-		sdUitDuration := 0.0
+	result.TotalCost = optimizer.objectives.Cost(reizen, terms)
+	result.Reizen = reizen
+	result.Penalty = 1
+
+	if groepCost != nil {
+		groepCost.Objectives = terms
+		groepCost.Promovendi = int(promovendi)
+		groepCost.Kampioenen = int(kampioenen)
+		groepCost.Degradanten = int(degradanten)
+		groepCost.Verenigingen = verenigingen
+		groepCost.TravelCost = result.TotalCost
+	}
 
-		for _, ti := range travelInfos {
-			sdUitDistance += math.Pow(float64(ti.Distance)-meanUitDistance, 2)
-			sdUitDuration += math.Pow(float64(ti.Duration)-meanUitDuration, 2)
-		}
+	if optimizer.herhaling != nil {
+		pairs := optimizer.herhaling.pairs(teams)
+		herhalingCost := optimizer.herhaling.cost(len(pairs))
+		result.TotalCost += herhalingCost
 
-		sdUitDistance = math.Sqrt(sdUitDistance / float64(len(travelInfos)-1))
-		sdUitDuration = math.Sqrt(sdUitDuration / float64(len(travelInfos)-1))
+		if groepCost != nil {
+			for _, pair := range pairs {
+				groepCost.Herhalingen = append(groepCost.Herhalingen, optimizer.herhaling.paar(pair))
+			}
+			groepCost.HerhalingCost = herhalingCost
+		}
+	}
 
-		//log.Printf("lotNr=%d, teamID=%v, meanAllDistance=%v, meanAllDuration=%v, meanUitDistance=%v, meanUitDuration=%v, sdUitDistance=%v, sdUitDuration=%v, totalDistance=%v, totalDuration=%v, len(travelInfos)=%d",
-		//	lotNR, teamID, meanAllDistance, meanAllDuration, meanUitDistance, meanUitDuration, sdUitDistance, sdUitDuration, totalDistance, totalDuration, len(travelInfos))
+	//penalties
+	if (promovendi+kampioenen) != 2 || degradanten != 1 {
+		penalized := penalize(result.TotalCost, pdPenalty)
 
-		result.TotalDistance += totalDistance
-		result.TotalDuration += totalDuration
-		result.TotalCost += uint64((meanAllDistance * sdUitDistance) + (meanAllDuration * sdUitDuration))
-		//result.TotalCost += uint64(meanAllDistance * sdUitDistance)
+		if groepCost != nil {
+			groepCost.addPenalty(fmt.Sprintf("%d promovendi or kampioenen and %d degradanten instead of 2 and 1", promovendi+kampioenen, degradanten),
+				pdPenalty, result.TotalCost, penalized)
+		}
 
+		result.TotalCost = penalized
+		result.Penalty *= pdPenalty
 	}
 
-	//evaluate whish list
-	//foreach whish not granted add cost penalty
-	//for _,wish := wishlist
-	//
+	if verenigingen != 10 {
+		penalized := penalize(result.TotalCost, verenigingPenalty)
 
-	//penalties
-	if (promovendi+kampioenen) != 2 || degradanten != 1 {
-		//log.Println("Penalty: ", result.TotalCost, " => ", uint64(float64(result.TotalCost)*1.2))
-		result.TotalCost = uint64(float64(result.TotalCost) * 1.9)
+		if groepCost != nil {
+			groepCost.addPenalty(fmt.Sprintf("%d verenigingen for %d teams", verenigingen, len(teams)),
+				verenigingPenalty, result.TotalCost, penalized)
+		}
+
+		result.TotalCost = penalized
+		result.Penalty *= verenigingPenalty
 	}
 
-	if len(verenigingen) != 10 {
-		result.TotalCost = uint64(float64(result.TotalCost) * 2.5)
+	if groepCost != nil {
+		groepCost.TotalCost = result.TotalCost
 	}
 
 	return result
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+//testSchemaWedstrijden of 10 loten by the circle method, the fixed lot
+//alternates thuis and uit
+func testSchemaWedstrijden() []WedstrijdJSON {
+	var wedstrijden []WedstrijdJSON
+
+	for ronde := 0; ronde < 9; ronde++ {
+		thuis, uit := 10, ronde+1
+		if ronde%2 == 1 {
+			thuis, uit = uit, thuis
+		}
+		wedstrijden = append(wedstrijden, WedstrijdJSON{ronde + 1, thuis, uit})
+
+		for k := 1; k <= 4; k++ {
+			wedstrijden = append(wedstrijden, WedstrijdJSON{ronde + 1, (ronde+k)%9 + 1, (ronde-k+9)%9 + 1})
+		}
+	}
+
+	return wedstrijden
+}
+
+//testSchema of testSchemaWedstrijden
+func testSchema(t *testing.T) *SpeelSchema {
+	ss, err := speelSchemaOfWedstrijden("test", testSchemaWedstrijden())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ss
+}
+
+//testRecords in the layout of the Indeling sheet of 15 verenigingen with 2
+//teams each: the first team of verenigingen 1 to 10 plays Meester klasse, all
+//others Eerste klasse. Vereniging v is 0100vv in Plaats((v-1)%5+1), its teams
+//are 0100vv1 and 0100vv2
+func testRecords() [][]string {
+	records := [][]string{{"Teamid", "zoek", "Teams", "Id", "Plaats", "P/D", "kl16/17", "Lot 16/17"}}
+
+	for v := 1; v <= 15; v++ {
+		id := fmt.Sprintf("0100%02d", v)
+		plaats := fmt.Sprintf("Plaats%d", (v-1)%5+1)
+
+		klasse := "1"
+		if v <= 10 {
+			klasse = "M"
+		}
+
+		records = append(records,
+			[]string{id + "1", klasse, fmt.Sprintf("V%d 1", v), id, plaats, "", "", ""},
+			[]string{id + "2", "1", fmt.Sprintf("V%d 2", v), id, plaats, "", "", ""})
+	}
+
+	return records
+}
+
+//testDistances between the 5 plaatsen, 10 km and 10 minutes per step
+func testDistances() []TravelInformation {
+	var info []TravelInformation
+
+	for a := 1; a <= 5; a++ {
+		for b := a + 1; b <= 5; b++ {
+			info = append(info, TravelInformation{
+				[2]string{CityName(fmt.Sprintf("Plaats%d", a)), CityName(fmt.Sprintf("Plaats%d", b))},
+				uint64(b-a) * 10000,
+				uint64(b-a) * 600,
+			})
+		}
+	}
+
+	return info
+}
+
+//newTestOptimizer with the test teams, sets the global optimizer
+func newTestOptimizer(t *testing.T) *Optimizer {
+	sb, err := loadSchaakbondSheet(sheetOfRecords("test", testRecords()), false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matrix := CreateTeamTravelCostInformationMatrix(sb, CreateDistanceMatrixWithTravelInformations(testDistances()))
+	optimizer = NewOptimizer(matrix, testSchema(t), sb)
+	return optimizer
+}
+
+//testVector with the teams of every klasse in the order of their ids
+func testVector(t *testing.T) Vector {
+	var teamIDs []string
+
+	for _, record := range testRecords()[1:] {
+		if record[1] == "M" {
+			teamIDs = append(teamIDs, record[0])
+		}
+	}
+
+	for _, record := range testRecords()[1:] {
+		if record[1] == "1" {
+			teamIDs = append(teamIDs, record[0])
+		}
+	}
+
+	X, err := optimizer.VectorFromTeamIDs(teamIDs)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return X
+}
+
+func TestNewOptimizer(t *testing.T) {
+	newTestOptimizer(t)
+
+	tests := []struct {
+		position int
+		groep    string
+	}{
+		{0, "M"},
+		{9, "M"},
+		{10, "1A"},
+		{19, "1A"},
+		{20, "1B"},
+		{29, "1B"},
+	}
+
+	for _, test := range tests {
+		if naam := optimizer.descriptions[test.position].Naam(); naam != test.groep {
+			t.Errorf("position %d: groep %v, expected %v", test.position, naam, test.groep)
+		}
+	}
+}
+
+func TestEvaluateAllocations(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	//the TravelCosts and the reizen of the groep
+	allocs := testing.AllocsPerRun(100, func() {
+		optimizer.Evaluate(X[:GroepGrootte])
+	})
+
+	if allocs > 2 {
+		t.Errorf("%v allocations per groep", allocs)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MaxHalford/gago"
+)
+
+//paretoDoelen names of the objectives of the pareto mode, all minimized
+var paretoDoelen = []string{"kilometers", "spreiding", "wensen niet granted"}
+
+//ParetoIndeling of the pareto mode with its objective values
+type ParetoIndeling struct {
+	Vector Vector
+	//Doelen values in the order of paretoDoelen
+	Doelen []float64
+	//Overtredingen number of groep penalties that fire and of teams above the
+	//cap of the minimax, an indeling with fewer overtredingen always dominates
+	Overtredingen int
+
+	rank     int
+	crowding float64
+}
+
+//NewParetoIndeling of a vector, evaluating its objectives: the km travelled
+//by all teams, the standard deviation of the km of a team over all teams and
+//the number of wensen not granted
+func NewParetoIndeling(X Vector) *ParetoIndeling {
+	indeling := new(ParetoIndeling)
+	indeling.Vector = X
+
+	breakdown := X.Explain()
+
+	var teamKilometers []float64
+	var kilometers float64
+
+	for _, groepCost := range breakdown.Groepen {
+		indeling.Overtredingen += len(groepCost.Penalties)
+
+		for _, teamCost := range groepCost.Teams {
+			km := float64(teamCost.Reis.TotalDistance) / 1000.0
+			teamKilometers = append(teamKilometers, km)
+			kilometers += km
+		}
+	}
+
+	//every team above the cap of the minimax counts as an overtreding
+	for _, km := range breakdown.Minimax {
+		indeling.Overtredingen += len(km.BovenCap)
+	}
+
+	mean := kilometers / float64(len(teamKilometers))
+	spreiding := 0.0
+	for _, km := range teamKilometers {
+		spreiding += math.Pow(km-mean, 2)
+	}
+	spreiding = math.Sqrt(spreiding / float64(len(teamKilometers)))
+
+	wensen := 0
+	for _, wens := range breakdown.Wensen {
+		if !wens.Granted {
+			wensen++
+		}
+	}
+
+	indeling.Doelen = []float64{kilometers, spreiding, float64(wensen)}
+	return indeling
+}
+
+//dominates when a has fewer overtredingen, or as many and is nowhere worse
+//and somewhere better than b
+func (a *ParetoIndeling) dominates(b *ParetoIndeling) bool {
+	if a.Overtredingen != b.Overtredingen {
+		return a.Overtredingen < b.Overtredingen
+	}
+
+	better := false
+	for ix := range a.Doelen {
+		if a.Doelen[ix] > b.Doelen[ix] {
+			return false
+		}
+		if a.Doelen[ix] < b.Doelen[ix] {
+			better = true
+		}
+	}
+	return better
+}
+
+//nonDominatedSort into fronts, setting the rank of every indeling
+func nonDominatedSort(indelingen []*ParetoIndeling) [][]*ParetoIndeling {
+	dominatedBy := make([]int, len(indelingen))
+	dominating := make([][]int, len(indelingen))
+	var fronts [][]*ParetoIndeling
+	var current []int
+
+	for p := range indelingen {
+		for q := range indelingen {
+			if indelingen[p].dominates(indelingen[q]) {
+				dominating[p] = append(dominating[p], q)
+			} else if indelingen[q].dominates(indelingen[p]) {
+				dominatedBy[p]++
+			}
+		}
+
+		if dominatedBy[p] == 0 {
+			current = append(current, p)
+		}
+	}
+
+	for rank := 0; len(current) > 0; rank++ {
+		front := make([]*ParetoIndeling, len(current))
+		var next []int
+
+		for ix, p := range current {
+			indelingen[p].rank = rank
+			front[ix] = indelingen[p]
+
+			for _, q := range dominating[p] {
+				dominatedBy[q]--
+				if dominatedBy[q] == 0 {
+					next = append(next, q)
+				}
+			}
+		}
+
+		fronts = append(fronts, front)
+		current = next
+	}
+
+	return fronts
+}
+
+//crowdingDistance of the indelingen of a front, the boundaries of every
+//objective get an infinite distance
+func crowdingDistance(front []*ParetoIndeling) {
+	for _, indeling := range front {
+		indeling.crowding = 0
+	}
+
+	for d := range paretoDoelen {
+		sort.SliceStable(front, func(i, j int) bool { return front[i].Doelen[d] < front[j].Doelen[d] })
+
+		lowest := front[0].Doelen[d]
+		highest := front[len(front)-1].Doelen[d]
+		front[0].crowding = math.Inf(1)
+		front[len(front)-1].crowding = math.Inf(1)
+
+		if highest == lowest {
+			continue
+		}
+
+		for ix := 1; ix < len(front)-1; ix++ {
+			front[ix].crowding += (front[ix+1].Doelen[d] - front[ix-1].Doelen[d]) / (highest - lowest)
+		}
+	}
+}
+
+//better by rank, and by crowding distance within a rank
+func (a *ParetoIndeling) better(b *ParetoIndeling) bool {
+	if a.rank != b.rank {
+		return a.rank < b.rank
+	}
+	return a.crowding > b.crowding
+}
+
+//tournament between two random indelingen
+func tournament(indelingen []*ParetoIndeling, rng *rand.Rand) *ParetoIndeling {
+	a := indelingen[rng.Intn(len(indelingen))]
+	b := indelingen[rng.Intn(len(indelingen))]
+
+	if b.better(a) {
+		return b
+	}
+	return a
+}
+
+//Pareto runs NSGA-II with the genetic operators of Vector
+type Pareto struct {
+	Indelingen []*ParetoIndeling
+	rng        *rand.Rand
+	size       int
+}
+
+//NewPareto with a population made by makeVector, seeded with the given vectors
+func NewPareto(size int, rng *rand.Rand, makeVector func(rng *rand.Rand) gago.Genome, seeds ...Vector) *Pareto {
+	pareto := new(Pareto)
+	pareto.rng = rng
+	pareto.size = size
+
+	for _, seed := range seeds {
+		if len(pareto.Indelingen) < size {
+			optimizer.Repair(seed, rng)
+			pareto.Indelingen = append(pareto.Indelingen, NewParetoIndeling(seed))
+		}
+	}
+
+	for len(pareto.Indelingen) < size {
+		pareto.Indelingen = append(pareto.Indelingen, NewParetoIndeling(makeVector(rng).(Vector)))
+	}
+
+	for _, front := range nonDominatedSort(pareto.Indelingen) {
+		crowdingDistance(front)
+	}
+
+	return pareto
+}
+
+//Enhance the population by one generation: as many children as parents,
+//from which the best fronts survive
+func (pareto *Pareto) Enhance() {
+	offspring := make([]*ParetoIndeling, 0, pareto.size)
+
+	for len(offspring) < pareto.size {
+		a := tournament(pareto.Indelingen, pareto.rng).Vector
+		b := tournament(pareto.Indelingen, pareto.rng).Vector
+
+		c, d := a.Crossover(b, pareto.rng)
+		for _, child := range []Vector{c.(Vector), d.(Vector)} {
+			child.Mutate(pareto.rng)
+			offspring = append(offspring, NewParetoIndeling(child))
+		}
+	}
+
+	combined := append(append([]*ParetoIndeling{}, pareto.Indelingen...), offspring...)
+	survivors := make([]*ParetoIndeling, 0, pareto.size)
+
+	for _, front := range nonDominatedSort(combined) {
+		crowdingDistance(front)
+
+		if len(survivors)+len(front) > pareto.size {
+			sort.SliceStable(front, func(i, j int) bool { return front[i].crowding > front[j].crowding })
+			survivors = append(survivors, front[:pareto.size-len(survivors)]...)
+			break
+		}
+
+		survivors = append(survivors, front...)
+	}
+
+	pareto.Indelingen = survivors
+}
+
+//Front of non-dominated indelingen without duplicates, sorted by kilometers
+func (pareto *Pareto) Front() []*ParetoIndeling {
+	var front []*ParetoIndeling
+	seen := make(map[string]bool)
+
+	for _, indeling := range pareto.Indelingen {
+		key := fmt.Sprint(indeling.Vector)
+
+		if indeling.rank == 0 && !seen[key] {
+			seen[key] = true
+			front = append(front, indeling)
+		}
+	}
+
+	sort.Slice(front, func(i, j int) bool { return front[i].Doelen[0] < front[j].Doelen[0] })
+	return front
+}
+
+//ExportParetoCSV with a row per indeling of the front: its objective values,
+//the team ids in the order of the vector and the seed of the run
+func ExportParetoCSV(fileName string, front []*ParetoIndeling) error {
+	file, err := os.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write(append(append([]string{"nr"}, paretoDoelen...), "overtredingen", "cost", "teams", "seed"))
+
+	for ix, indeling := range front {
+		record := []string{strconv.Itoa(ix + 1)}
+		for _, doel := range indeling.Doelen {
+			record = append(record, strconv.FormatFloat(doel, 'f', 1, 64))
+		}
+		record = append(record,
+			strconv.Itoa(indeling.Overtredingen),
+			strconv.FormatFloat(indeling.Vector.Evaluate(), 'f', 0, 64),
+			strings.Join(indeling.Vector.TranslateToTeamIDs(), " "),
+			strconv.FormatInt(*seed, 10))
+		writer.Write(record)
+	}
+
+	writer.Flush()
+
+	if err = writer.Error(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
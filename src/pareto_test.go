@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+//paretoIndelingen with the doelen of every indeling, named by their index
+func paretoIndelingen(doelen ...[]float64) []*ParetoIndeling {
+	indelingen := make([]*ParetoIndeling, len(doelen))
+
+	for ix, d := range doelen {
+		indelingen[ix] = &ParetoIndeling{Doelen: d}
+	}
+
+	return indelingen
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	tests := []struct {
+		naam          string
+		doelen        [][]float64
+		overtredingen []int
+		//ranks of the indelingen
+		ranks []int
+	}{
+		{"single", [][]float64{{1, 1, 0}}, nil, []int{0}},
+		{"trade-off", [][]float64{{1, 3, 0}, {2, 2, 0}, {3, 1, 0}}, nil, []int{0, 0, 0}},
+		{"dominated", [][]float64{{1, 1, 0}, {2, 2, 0}, {3, 3, 0}}, nil, []int{0, 1, 2}},
+		{"equal is not dominated", [][]float64{{1, 1, 0}, {1, 1, 0}}, nil, []int{0, 0}},
+		{"better in one doel", [][]float64{{1, 1, 1}, {1, 1, 0}}, nil, []int{1, 0}},
+		{"two fronts", [][]float64{{1, 4, 0}, {4, 1, 0}, {2, 5, 0}, {5, 2, 0}, {2, 2, 0}}, nil, []int{0, 0, 1, 1, 0}},
+		{"overtredingen first", [][]float64{{1, 1, 0}, {9, 9, 9}}, []int{1, 0}, []int{1, 0}},
+	}
+
+	for _, test := range tests {
+		indelingen := paretoIndelingen(test.doelen...)
+
+		for ix, o := range test.overtredingen {
+			indelingen[ix].Overtredingen = o
+		}
+
+		fronts := nonDominatedSort(indelingen)
+
+		count := 0
+		for rank, front := range fronts {
+			for _, indeling := range front {
+				if indeling.rank != rank {
+					t.Errorf("%v: indeling in front %d has rank %d", test.naam, rank, indeling.rank)
+				}
+				count++
+			}
+		}
+
+		if count != len(indelingen) {
+			t.Errorf("%v: %d indelingen in the fronts, expected %d", test.naam, count, len(indelingen))
+		}
+
+		for ix, indeling := range indelingen {
+			if indeling.rank != test.ranks[ix] {
+				t.Errorf("%v: indeling %d has rank %d, expected %d", test.naam, ix, indeling.rank, test.ranks[ix])
+			}
+		}
+	}
+}
+
+func TestCrowdingDistance(t *testing.T) {
+	inf := math.Inf(1)
+
+	tests := []struct {
+		naam   string
+		doelen [][]float64
+		//crowding of the indelingen
+		crowding []float64
+	}{
+		{"single", [][]float64{{1, 1, 0}}, []float64{inf}},
+		{"boundaries", [][]float64{{1, 3, 0}, {3, 1, 0}}, []float64{inf, inf}},
+		//the middle one spans the front in the first two doelen, the third is equal
+		{"middle", [][]float64{{1, 3, 0}, {2, 2, 0}, {3, 1, 0}}, []float64{inf, 2, inf}},
+		{"closer neighbours", [][]float64{{0, 4, 0}, {1, 3, 0}, {3, 1, 0}, {4, 0, 0}}, []float64{inf, 1.5, 1.5, inf}},
+		{"unordered", [][]float64{{3, 1, 0}, {0, 4, 0}, {4, 0, 0}, {1, 3, 0}}, []float64{1.5, inf, inf, 1.5}},
+	}
+
+	for _, test := range tests {
+		indelingen := paretoIndelingen(test.doelen...)
+		front := append([]*ParetoIndeling{}, indelingen...)
+
+		crowdingDistance(front)
+
+		for ix, indeling := range indelingen {
+			if indeling.crowding != test.crowding[ix] {
+				t.Errorf("%v: indeling %d has crowding %v, expected %v", test.naam, ix, indeling.crowding, test.crowding[ix])
+			}
+		}
+	}
+}
+
+func TestParetoBetter(t *testing.T) {
+	tests := []struct {
+		a, b   ParetoIndeling
+		better bool
+	}{
+		{ParetoIndeling{rank: 0, crowding: 1}, ParetoIndeling{rank: 1, crowding: 5}, true},
+		{ParetoIndeling{rank: 1, crowding: 5}, ParetoIndeling{rank: 0, crowding: 1}, false},
+		{ParetoIndeling{rank: 1, crowding: 5}, ParetoIndeling{rank: 1, crowding: 1}, true},
+		{ParetoIndeling{rank: 1, crowding: 1}, ParetoIndeling{rank: 1, crowding: 1}, false},
+	}
+
+	for ix, test := range tests {
+		if better := test.a.better(&test.b); better != test.better {
+			t.Errorf("%d: better %v, expected %v", ix, better, test.better)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"sort"
+)
+
+//maxSwapAttempts of a mutation to find a position it may swap with
+const maxSwapAttempts = 100
+
+//Pin of a team to a groep, and to a lot unless Lot is 0
+type Pin struct {
+	Team  string `json:"team"`
+	Groep string `json:"groep"`
+	Lot   int    `json:"lot"`
+}
+
+//Pins of teams and locked groepen, every locked groep lists its teams in the
+//order of the loten
+type Pins struct {
+	Pins    []Pin               `json:"pins"`
+	Groepen map[string][]string `json:"groepen"`
+}
+
+//LoadPins from a json file
+func LoadPins(fileName string) (*Pins, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pins := new(Pins)
+	err = json.Unmarshal(file, pins)
+
+	if err != nil {
+		return nil, err
+	}
+
+	//a locked groep pins every team to its lot
+	groepen := make([]string, 0, len(pins.Groepen))
+	for groep := range pins.Groepen {
+		groepen = append(groepen, groep)
+	}
+	sort.Strings(groepen)
+
+	for _, groep := range groepen {
+		teams := pins.Groepen[groep]
+
+		if len(teams) != GroepGrootte {
+			return nil, fmt.Errorf("Locked groep %v has %d teams instead of %d", groep, len(teams), GroepGrootte)
+		}
+
+		for ix, teamID := range teams {
+			pins.Pins = append(pins.Pins, Pin{teamID, groep, ix + 1})
+		}
+	}
+
+	return pins, nil
+}
+
+//groepPin of a team which must stay in a groep, on any lot
+type groepPin struct {
+	team        TeamCostID
+	description *Description
+}
+
+//lotPin of a team which must stay on a position
+type lotPin struct {
+	team     TeamCostID
+	position int
+}
+
+//findGroep by naam, nil if there is no such groep
+func (optimizer *Optimizer) findGroep(naam string) *Description {
+	for _, description := range optimizer.descriptions {
+		if description != nil && description.Naam() == naam {
+			return description
+		}
+	}
+	return nil
+}
+
+//Pin teams to their groepen and loten, so the genetic operators only
+//permute the remaining free positions
+func (optimizer *Optimizer) Pin(pins *Pins) error {
+	pinned := make(map[string]bool)
+	perGroep := make(map[*Description]int)
+
+	for _, pin := range pins.Pins {
+		info := optimizer.matrix.teamCostIDByTeamID[pin.Team]
+
+		if info == nil {
+			return fmt.Errorf("Unknown team %v pinned to groep %v", pin.Team, pin.Groep)
+		}
+
+		if pinned[pin.Team] {
+			return fmt.Errorf("Team %v is pinned more than once", pin.Team)
+		}
+		pinned[pin.Team] = true
+
+		description := optimizer.findGroep(pin.Groep)
+
+		if description == nil {
+			return fmt.Errorf("Unknown groep %v of pinned team %v", pin.Groep, pin.Team)
+		}
+
+		if description.klasseGroup.klasse != info.team.klasse {
+			return fmt.Errorf("Team %v of klasse %v pinned to groep %v", pin.Team, info.team.klasse, pin.Groep)
+		}
+
+		perGroep[description]++
+		if perGroep[description] > GroepGrootte {
+			return fmt.Errorf("More than %d teams pinned to groep %v", GroepGrootte, pin.Groep)
+		}
+
+		if pin.Lot == 0 {
+			optimizer.groepPins = append(optimizer.groepPins, groepPin{info.teamCostID, description})
+			optimizer.pinnedGroep[info.teamCostID] = description
+			continue
+		}
+
+		if pin.Lot < 1 || pin.Lot > GroepGrootte {
+			return fmt.Errorf("Team %v pinned to lot %d of groep %v, loten are 1 to %d", pin.Team, pin.Lot, pin.Groep, GroepGrootte)
+		}
+
+		position := description.begin + pin.Lot - 1
+
+		if optimizer.fixed[position] {
+			return fmt.Errorf("Team %v pinned to lot %d of groep %v, which is already taken", pin.Team, pin.Lot, pin.Groep)
+		}
+
+		optimizer.lotPins = append(optimizer.lotPins, lotPin{info.teamCostID, position})
+		optimizer.fixed[position] = true
+	}
+
+	return nil
+}
+
+//Swappable positions a and b of a vector, without moving a pinned team
+func (optimizer *Optimizer) Swappable(X Vector, a, b int) bool {
+	if optimizer.fixed[a] || optimizer.fixed[b] {
+		return false
+	}
+
+	if description := optimizer.pinnedGroep[X[a]]; description != nil && optimizer.descriptions[b] != description {
+		return false
+	}
+
+	if description := optimizer.pinnedGroep[X[b]]; description != nil && optimizer.descriptions[a] != description {
+		return false
+	}
+
+	return true
+}
+
+//Repair a vector by swapping the pinned teams back to their lot or into
+//their groep, a vector without pins is left as is
+func (optimizer *Optimizer) Repair(X Vector, rng *rand.Rand) {
+	for _, pin := range optimizer.lotPins {
+		if q := positionOf(X, pin.team); q != pin.position {
+			X[q], X[pin.position] = X[pin.position], X[q]
+		}
+	}
+
+	//moving a team into its groep can move a team pinned to another groep
+	//out of it, so repeat until every pinned team is in place
+	for pass := 0; pass < 3; pass++ {
+		moved := false
+
+		for _, pin := range optimizer.groepPins {
+			q := positionOf(X, pin.team)
+			description := pin.description
+
+			if q >= description.begin && q <= description.end {
+				continue
+			}
+
+			//a free position of the groep whose team isn't pinned, else one
+			//with a team pinned to another groep
+			candidates := optimizer.freePositions(X, description, true)
+			if len(candidates) == 0 {
+				candidates = optimizer.freePositions(X, description, false)
+			}
+
+			p := candidates[rng.Intn(len(candidates))]
+			X[q], X[p] = X[p], X[q]
+			moved = true
+		}
+
+		if !moved {
+			return
+		}
+	}
+}
+
+//freePositions of a groep which are not fixed and don't hold a team pinned
+//to it, or to any groep when unpinned is set
+func (optimizer *Optimizer) freePositions(X Vector, description *Description, unpinned bool) []int {
+	positions := make([]int, 0, GroepGrootte)
+
+	for p := description.begin; p <= description.end; p++ {
+		pinnedTo := optimizer.pinnedGroep[X[p]]
+
+		if !optimizer.fixed[p] && pinnedTo != description && (!unpinned || pinnedTo == nil) {
+			positions = append(positions, p)
+		}
+	}
+
+	return positions
+}
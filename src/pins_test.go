@@ -0,0 +1,155 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestPin(t *testing.T) {
+	tests := []struct {
+		naam string
+		pins []Pin
+		fout string
+	}{
+		{"lot and groep", []Pin{{"0100011", "M", 3}, {"0100012", "1B", 0}}, ""},
+		{"unknown team", []Pin{{"0100999", "M", 1}}, "Unknown team"},
+		{"twice", []Pin{{"0100012", "1A", 0}, {"0100012", "1B", 0}}, "more than once"},
+		{"unknown groep", []Pin{{"0100012", "1C", 0}}, "Unknown groep"},
+		{"other klasse", []Pin{{"0100011", "1A", 0}}, "of klasse M pinned to groep 1A"},
+		{"lot out of range", []Pin{{"0100012", "1A", 11}}, "loten are 1 to 10"},
+		{"lot taken", []Pin{{"0100012", "1A", 1}, {"0100022", "1A", 1}}, "already taken"},
+	}
+
+	for _, test := range tests {
+		newTestOptimizer(t)
+		err := optimizer.Pin(&Pins{Pins: test.pins})
+
+		if test.fout == "" && err != nil {
+			t.Errorf("%v: %v", test.naam, err)
+		}
+
+		if test.fout != "" && (err == nil || !strings.Contains(err.Error(), test.fout)) {
+			t.Errorf("%v: error %v, expected %v", test.naam, err, test.fout)
+		}
+	}
+}
+
+func TestLoadPinsGroepen(t *testing.T) {
+	tests := []struct {
+		naam    string
+		content string
+		pins    int
+		fout    string
+	}{
+		{"pins", `{ "pins": [ { "team": "0100012", "groep": "1A" } ] }`, 1, ""},
+		{"locked groep", `{ "groepen": { "M": ["0100011", "0100021", "0100031", "0100041", "0100051", "0100061", "0100071", "0100081", "0100091", "0100101"] } }`, 10, ""},
+		{"short groep", `{ "groepen": { "M": ["0100011"] } }`, 0, "has 1 teams instead of 10"},
+	}
+
+	for _, test := range tests {
+		fileName, remove := writeTestFile(t, "pins.json", test.content)
+		pins, err := LoadPins(fileName)
+		remove()
+
+		if test.fout != "" {
+			if err == nil || !strings.Contains(err.Error(), test.fout) {
+				t.Errorf("%v: error %v, expected %v", test.naam, err, test.fout)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%v: %v", test.naam, err)
+		} else if len(pins.Pins) != test.pins {
+			t.Errorf("%v: %d pins, expected %d", test.naam, len(pins.Pins), test.pins)
+		}
+	}
+}
+
+func TestSwappable(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	//0100011 on position 2, 0100012 in groep 1A at position 10
+	if err := optimizer.Pin(&Pins{Pins: []Pin{{"0100011", "M", 3}, {"0100012", "1A", 0}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		naam      string
+		a, b      int
+		swappable bool
+	}{
+		{"free positions", 0, 5, true},
+		{"lot pin", 2, 5, false},
+		{"lot pin as b", 5, 2, false},
+		{"groep pin within its groep", 10, 15, true},
+		{"groep pin out of its groep", 10, 25, false},
+		{"groep pin as b", 25, 10, false},
+		{"unpinned teams between groepen", 11, 25, true},
+	}
+
+	for _, test := range tests {
+		if s := optimizer.Swappable(X, test.a, test.b); s != test.swappable {
+			t.Errorf("%v: swappable %v, expected %v", test.naam, s, test.swappable)
+		}
+	}
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		naam string
+		pins []Pin
+	}{
+		{"no pins", nil},
+		{"lot pins", []Pin{{"0100011", "M", 3}, {"0100112", "1A", 1}, {"0100012", "1B", 10}}},
+		{"groep pins", []Pin{{"0100012", "1A", 0}, {"0100022", "1B", 0}, {"0100032", "1B", 0}}},
+		{"full groep", []Pin{
+			{"0100012", "1B", 0}, {"0100022", "1B", 0}, {"0100032", "1B", 0}, {"0100042", "1B", 0}, {"0100052", "1B", 0},
+			{"0100062", "1B", 0}, {"0100072", "1B", 0}, {"0100082", "1B", 0}, {"0100092", "1B", 0}, {"0100102", "1B", 1},
+		}},
+	}
+
+	for _, test := range tests {
+		newTestOptimizer(t)
+
+		if err := optimizer.Pin(&Pins{Pins: test.pins}); err != nil {
+			t.Fatalf("%v: %v", test.naam, err)
+		}
+
+		X := testVector(t)
+		rng := rand.New(rand.NewSource(1))
+
+		for run := 0; run < 20; run++ {
+			//shuffle the teams within their klasse
+			for _, kg := range optimizer.klasseGroups {
+				if kg == nil || len(kg.teams) == 0 {
+					continue
+				}
+				for ix, v := range rng.Perm(len(kg.teams)) {
+					X[kg.begin+ix] = kg.teams[v]
+				}
+			}
+
+			optimizer.Repair(X, rng)
+
+			if _, err := optimizer.VectorFromTeamIDs(X.TranslateToTeamIDs()); err != nil {
+				t.Fatalf("%v: %v", test.naam, err)
+			}
+
+			for _, pin := range test.pins {
+				description := optimizer.findGroep(pin.Groep)
+				q := positionOf(X, optimizer.matrix.GetTeamCostID(pin.Team))
+
+				if q < description.begin || q > description.end {
+					t.Errorf("%v: team %v on position %d outside groep %v", test.naam, pin.Team, q, pin.Groep)
+				}
+
+				if pin.Lot > 0 && q != description.begin+pin.Lot-1 {
+					t.Errorf("%v: team %v on lot %d instead of %d", test.naam, pin.Team, q-description.begin+1, pin.Lot)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+//ProgrammaWedstrijd of an indeling, played at the plaats of the thuis team
+type ProgrammaWedstrijd struct {
+	Groep     string `json:"groep"`
+	Ronde     int    `json:"ronde"`
+	ThuisLot  int    `json:"thuisLot"`
+	Thuis     string `json:"thuis"`
+	ThuisNaam string `json:"thuisNaam"`
+	UitLot    int    `json:"uitLot"`
+	Uit       string `json:"uit"`
+	UitNaam   string `json:"uitNaam"`
+	Plaats    string `json:"plaats"`
+	//Afstand in meters
+	//Reistijd in seconds
+	Afstand  uint64 `json:"afstand"`
+	Reistijd uint64 `json:"reistijd"`
+}
+
+//Programma of every groep of the vector, expanded through the rondes of the
+//speel schema
+func (X Vector) Programma() []ProgrammaWedstrijd {
+	programma := make([]ProgrammaWedstrijd, 0, len(X)/2*len(optimizer.schema.Rondes))
+
+	for _, description := range X.groepen() {
+		for ronde, r := range optimizer.schema.Rondes {
+			for _, w := range r.Wedstrijden {
+				thuisID := X[description.begin+int(w.Thuis)]
+				uitID := X[description.begin+int(w.Uit)]
+				thuis := optimizer.matrix.GetTeamInfoByCostID(thuisID).team
+				uit := optimizer.matrix.GetTeamInfoByCostID(uitID).team
+
+				var pw ProgrammaWedstrijd
+				pw.Groep = description.Naam()
+				pw.Ronde = ronde + 1
+				pw.ThuisLot = int(w.Thuis) + 1
+				pw.Thuis = thuis.id
+				pw.ThuisNaam = thuis.naam
+				pw.UitLot = int(w.Uit) + 1
+				pw.Uit = uit.id
+				pw.UitNaam = uit.naam
+				pw.Plaats = thuis.vereniging.plaats
+
+				if travelInfo := optimizer.matrix.GetTeamsTravelCost(thuisID, uitID); travelInfo != nil {
+					pw.Afstand = travelInfo.Distance
+					pw.Reistijd = travelInfo.Duration
+				}
+
+				programma = append(programma, pw)
+			}
+		}
+	}
+
+	return programma
+}
+
+//ExportProgrammaCSV with a row per wedstrijd, the seed of the run in the
+//last column
+func ExportProgrammaCSV(fileName string, programma []ProgrammaWedstrijd) error {
+	file, err := os.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"groep", "ronde", "thuisLot", "thuis", "thuisNaam", "uitLot", "uit", "uitNaam", "plaats", "afstand", "reistijd", "seed"})
+
+	for _, pw := range programma {
+		writer.Write([]string{
+			pw.Groep,
+			strconv.Itoa(pw.Ronde),
+			strconv.Itoa(pw.ThuisLot),
+			pw.Thuis,
+			pw.ThuisNaam,
+			strconv.Itoa(pw.UitLot),
+			pw.Uit,
+			pw.UitNaam,
+			pw.Plaats,
+			strconv.FormatUint(pw.Afstand, 10),
+			strconv.FormatUint(pw.Reistijd, 10),
+			strconv.FormatInt(*seed, 10),
+		})
+	}
+
+	writer.Flush()
+
+	if err = writer.Error(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+//ExportProgrammaJSON as an object { "seed": 42, "wedstrijden": [ ProgrammaWedstrijd, ... ] }
+func ExportProgrammaJSON(fileName string, programma []ProgrammaWedstrijd) error {
+	b, err := json.MarshalIndent(struct {
+		Seed        int64                `json:"seed"`
+		Wedstrijden []ProgrammaWedstrijd `json:"wedstrijden"`
+	}{*seed, programma}, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, b, 0644)
+}
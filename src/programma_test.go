@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+//testPlaats of a test team, vereniging v plays in Plaats((v-1)%5+1)
+func testPlaats(t *testing.T, teamID string) int {
+	v, err := strconv.Atoi(teamID[4:6])
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return (v-1)%5 + 1
+}
+
+func TestProgramma(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	programma := X.Programma()
+	wedstrijden := testSchemaWedstrijden()
+	groepen := []string{"M", "1A", "1B"}
+
+	if len(programma) != len(groepen)*len(wedstrijden) {
+		t.Fatalf("%d wedstrijden, expected %d", len(programma), len(groepen)*len(wedstrijden))
+	}
+
+	for g, groep := range groepen {
+		//the wedstrijden of a groep per ronde in the order of the schema
+		for ix, w := range wedstrijden {
+			pw := programma[g*len(wedstrijden)+ix]
+			thuis := X[g*GroepGrootte+w.Thuis-1]
+			uit := X[g*GroepGrootte+w.Uit-1]
+			thuisID := optimizer.matrix.GetTeamInfoByCostID(thuis).team.id
+			uitID := optimizer.matrix.GetTeamInfoByCostID(uit).team.id
+
+			if pw.Groep != groep || pw.Ronde != w.Ronde || pw.ThuisLot != w.Thuis || pw.UitLot != w.Uit {
+				t.Errorf("Wedstrijd %d of groep %v is %v %d: %d-%d, expected ronde %d: %d-%d", ix, groep, pw.Groep, pw.Ronde, pw.ThuisLot, pw.UitLot, w.Ronde, w.Thuis, w.Uit)
+			}
+
+			if pw.Thuis != thuisID || pw.Uit != uitID {
+				t.Errorf("Groep %v ronde %d: %v-%v, expected %v-%v", groep, pw.Ronde, pw.Thuis, pw.Uit, thuisID, uitID)
+			}
+
+			//Plaats a and b are |a-b| × 10 km apart, see testDistances
+			a, b := testPlaats(t, thuisID), testPlaats(t, uitID)
+			afstand := uint64(a-b) * 10000
+			if b > a {
+				afstand = uint64(b-a) * 10000
+			}
+
+			if pw.Plaats != fmt.Sprintf("Plaats%d", a) || pw.Afstand != afstand {
+				t.Errorf("Groep %v ronde %d: %v-%v in %v over %d m, expected Plaats%d over %d m", groep, pw.Ronde, pw.Thuis, pw.Uit, pw.Plaats, pw.Afstand, a, afstand)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"html/template"
+	"os"
+	"sort"
+	"time"
+)
+
+//ReportTeam row of a groep in the report
+type ReportTeam struct {
+	Lot        int
+	ID         string
+	Naam       string
+	Vereniging string
+	Plaats     string
+	PD         string
+	Reis       *Reis
+	Dubbel     bool
+}
+
+//ReportGroep of the report
+type ReportGroep struct {
+	Naam   string
+	Cost   *GroepCost
+	Dubbel []string
+	Teams  []ReportTeam
+}
+
+//Report of an indeling
+type Report struct {
+	Seed        int64
+	StopReason  string
+	Datum       string
+	Cost        float64
+	Baseline    float64
+	Vergelijk   float64
+	Objectives  string
+	Groepen     []ReportGroep
+	Minimax     []KlasseMinimax
+	Wensen      []WensCost
+	Wijzigingen []Wijziging
+	Conflicten  []ThuisConflict
+	Verhinderd  []VerhinderdConflict
+}
+
+//NewReport of the indeling of a vector
+func (X Vector) NewReport() *Report {
+	report := new(Report)
+	report.Seed = *seed
+	report.StopReason = stopReason
+	report.Datum = time.Now().Format("2006-01-02 15:04")
+	report.Baseline = baseline
+	report.Objectives = optimizer.objectives.String()
+
+	breakdown := X.Explain()
+	report.Cost = breakdown.Cost
+	//the cost compared with the baseline, which is without the repeated opponents
+	report.Vergelijk = report.Cost
+	if baseline > 0 && optimizer.herhaling != nil {
+		report.Vergelijk = X.zonderHerhaling()
+	}
+	report.Minimax = breakdown.Minimax
+	report.Wensen = breakdown.Wensen
+	report.Wijzigingen = breakdown.Wijzigingen
+	report.Conflicten = breakdown.ThuisConflicten
+	report.Verhinderd = breakdown.Verhinderd
+
+	for _, description := range X.groepen() {
+		teams := X[description.begin : description.end+1]
+
+		var groep ReportGroep
+		groep.Naam = description.Naam()
+		groep.Cost = optimizer.Explain(teams)
+
+		verenigingen := make(map[string]int)
+		for _, tid := range teams {
+			verenigingen[optimizer.matrix.GetTeamInfoByCostID(tid).team.vereniging.id]++
+		}
+
+		for id, count := range verenigingen {
+			if count > 1 {
+				groep.Dubbel = append(groep.Dubbel, id)
+			}
+		}
+		sort.Strings(groep.Dubbel)
+
+		for lotNR, tid := range teams {
+			t := optimizer.matrix.GetTeamInfoByCostID(tid).team
+
+			var rt ReportTeam
+			rt.Lot = lotNR + 1
+			rt.ID = t.id
+			rt.Naam = t.naam
+			rt.Vereniging = t.vereniging.id
+			rt.Plaats = t.vereniging.plaats
+			rt.PD = t.pd.Code()
+			rt.Reis = groep.Cost.Teams[lotNR].Reis
+			rt.Dubbel = verenigingen[t.vereniging.id] > 1
+
+			groep.Teams = append(groep.Teams, rt)
+		}
+
+		report.Groepen = append(report.Groepen, groep)
+	}
+
+	return report
+}
+
+//reportFuncs used by the report template, distances are in meters and
+//durations in seconds
+var reportFuncs = template.FuncMap{
+	"km": func(meters interface{}) float64 {
+		switch m := meters.(type) {
+		case uint64:
+			return float64(m) / 1000.0
+		case float64:
+			return m / 1000.0
+		}
+		return 0
+	},
+	"min": func(seconds interface{}) float64 {
+		switch s := seconds.(type) {
+		case uint64:
+			return float64(s) / 60.0
+		case float64:
+			return s / 60.0
+		}
+		return 0
+	},
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(`<!DOCTYPE html>
+<html lang="nl">
+<head>
+<meta charset="utf-8">
+<title>Indeling</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: right; }
+th { background: #eee; cursor: pointer; }
+td.tekst { text-align: left; }
+tr.dubbel td { background: #fee; }
+</style>
+</head>
+<body>
+<h1>Indeling</h1>
+<p>Seed {{.Seed}}, {{.Datum}}{{if .StopReason}}, stopped: {{.StopReason}}{{end}}. Cost {{printf "%.0f" .Cost}}{{if gt .Baseline 0.0}}, last season {{printf "%.0f" .Baseline}}{{if ne .Vergelijk .Cost}} against {{printf "%.0f" .Vergelijk}} without the repeated opponents{{end}}{{end}}.</p>
+<p>The reiskosten of a groep are {{.Objectives}}. In the standaard objective the cost of a team is the mean travel over all rondes (distance and duration) multiplied by the standard deviation of its uit wedstrijden. A groep is penalised when it doesn't have 2 promovendi or kampioenen and 1 degradant, or when a vereniging has more than one team in it.</p>
+
+<h2>Groepen</h2>
+<table class="sortable">
+<thead><tr><th>Groep</th><th>Reiskosten</th><th>Cost</th><th>P</th><th>K</th><th>D</th><th>Verenigingen met meer teams</th><th>Herhalingen</th><th>Penalties</th></tr></thead>
+<tbody>
+{{range .Groepen}}<tr><td class="tekst">{{.Naam}}</td><td>{{.Cost.TravelCost}}</td><td>{{.Cost.TotalCost}}</td><td>{{.Cost.Promovendi}}</td><td>{{.Cost.Kampioenen}}</td><td>{{.Cost.Degradanten}}</td><td class="tekst">{{range .Dubbel}}{{.}} {{end}}</td><td class="tekst">{{range .Cost.Herhalingen}}{{.}} {{end}}{{if .Cost.HerhalingCost}}(+{{.Cost.HerhalingCost}}){{end}}</td><td class="tekst">{{range .Cost.Penalties}}{{.Reden}}: &times;{{.Multiplier}} (+{{.Amount}})<br>{{end}}</td></tr>
+{{end}}</tbody>
+</table>
+{{if .Minimax}}
+<h2>Minimax</h2>
+<table class="sortable">
+<thead><tr><th>Klasse</th><th>Waarde</th><th>Term</th><th>Teams boven cap</th></tr></thead>
+<tbody>
+{{range .Minimax}}<tr><td class="tekst">{{.Klasse}}</td><td>{{printf "%.1f" .Waarde}}</td><td>{{printf "%.0f" .Term}}</td><td class="tekst">{{range .BovenCap}}{{.}} {{end}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+{{if .Wijzigingen}}
+<h2>Wijzigingen</h2>
+<table class="sortable">
+<thead><tr><th>Team</th><th>Van</th><th>Naar</th></tr></thead>
+<tbody>
+{{range .Wijzigingen}}<tr><td class="tekst">{{.Team}}</td><td class="tekst">{{.Van}}</td><td class="tekst">{{.Naar}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+{{if .Conflicten}}
+<h2>Thuis conflicten</h2>
+<table class="sortable">
+<thead><tr><th>Vereniging</th><th>Ronde</th><th>Teams thuis</th></tr></thead>
+<tbody>
+{{range .Conflicten}}<tr><td class="tekst">{{.Vereniging}}</td><td>{{.Ronde}}</td><td class="tekst">{{range .Teams}}{{.}} {{end}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+{{if .Verhinderd}}
+<h2>Verhinderd thuis</h2>
+<table class="sortable">
+<thead><tr><th>Team</th><th>Groep</th><th>Lot</th><th>Ronde</th></tr></thead>
+<tbody>
+{{range .Verhinderd}}<tr><td class="tekst">{{.Team}}</td><td class="tekst">{{.Groep}}</td><td>{{.Lot}}</td><td>{{.Ronde}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+{{if .Wensen}}
+<h2>Wensen</h2>
+<table class="sortable">
+<thead><tr><th>Wens</th><th>Granted</th><th>Multiplier</th></tr></thead>
+<tbody>
+{{range .Wensen}}<tr><td class="tekst">{{.Wens}}</td><td class="tekst">{{if .Granted}}ja{{else}}nee{{end}}</td><td>{{.Multiplier}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+{{range .Groepen}}
+<h2>{{.Naam}}</h2>
+<table class="sortable">
+<thead><tr><th>Lot</th><th>Team</th><th>Naam</th><th>Vereniging</th><th>Plaats</th><th>P/D</th><th>Uit</th><th>Afstand (km)</th><th>Reistijd (min)</th><th>Langste (km)</th><th>Langste (min)</th><th>Gem. uit (km)</th><th>Sd uit (km)</th><th>Gem. uit (min)</th><th>Sd uit (min)</th><th>Cost</th></tr></thead>
+<tbody>
+{{range .Teams}}<tr{{if .Dubbel}} class="dubbel"{{end}}><td>{{.Lot}}</td><td class="tekst">{{.ID}}</td><td class="tekst">{{.Naam}}</td><td class="tekst">{{.Vereniging}}</td><td class="tekst">{{.Plaats}}</td><td class="tekst">{{.PD}}</td><td>{{.Reis.UitCount}}</td><td>{{printf "%.1f" (km .Reis.TotalDistance)}}</td><td>{{printf "%.0f" (min .Reis.TotalDuration)}}</td><td>{{printf "%.1f" (km .Reis.LongestDistance)}}</td><td>{{printf "%.0f" (min .Reis.LongestDuration)}}</td><td>{{printf "%.1f" (km .Reis.MeanUitDistance)}}</td><td>{{printf "%.1f" (km .Reis.SdUitDistance)}}</td><td>{{printf "%.0f" (min .Reis.MeanUitDuration)}}</td><td>{{printf "%.0f" (min .Reis.SdUitDuration)}}</td><td>{{.Reis.Cost}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+
+<script>
+//sort a table on the clicked column, numbers numerically, click again to reverse
+document.querySelectorAll("table.sortable th").forEach(function (th) {
+	th.addEventListener("click", function () {
+		var table = th.closest("table");
+		var body = table.tBodies[0];
+		var column = Array.prototype.indexOf.call(th.parentNode.children, th);
+		var ascending = th.dataset.order !== "asc";
+		th.dataset.order = ascending ? "asc" : "desc";
+
+		var rows = Array.prototype.slice.call(body.rows);
+		rows.sort(function (a, b) {
+			var x = a.cells[column].textContent, y = b.cells[column].textContent;
+			var nx = parseFloat(x), ny = parseFloat(y);
+			var order = isNaN(nx) || isNaN(ny) ? x.localeCompare(y) : nx - ny;
+			return ascending ? order : -order;
+		});
+		rows.forEach(function (row) { body.appendChild(row); });
+	});
+});
+</script>
+</body>
+</html>
+`))
+
+//ExportHTML report of the indeling
+func (X Vector) ExportHTML(fileName string) error {
+	file, err := os.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	if err = reportTemplate.Execute(file, X.NewReport()); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestExportHTML(t *testing.T) {
+	tests := []struct {
+		naam     string
+		minimax  *Minimax
+		baseline float64
+		//bevat and ontbreekt in the report
+		bevat, ontbreekt []string
+	}{
+		{"standaard", nil, 0, []string{"<h2>Groepen</h2>"}, []string{"<h2>Minimax</h2>", "last season"}},
+		{"minimax", &Minimax{Gewicht: 1, Percentiel: 100, Eenheid: "km"}, 0, []string{"<h2>Minimax</h2>", "1×minimax"}, []string{"last season"}},
+		{"last season", nil, 1e12, []string{"last season 1000000000000"}, []string{"<h2>Minimax</h2>"}},
+	}
+
+	for _, test := range tests {
+		newTestOptimizer(t)
+		X := testVector(t)
+		optimizer.objectives.Minimax = test.minimax
+
+		*seed = 42
+		stopReason = "interrupted at generation 7"
+		baseline = test.baseline
+
+		fileName, remove := writeTestFile(t, "report.html", "")
+		err := X.ExportHTML(fileName)
+		content, rerr := ioutil.ReadFile(fileName)
+		remove()
+
+		*seed, stopReason, baseline = 0, "", 0
+
+		if err != nil || rerr != nil {
+			t.Fatalf("%v: %v %v", test.naam, err, rerr)
+		}
+
+		report := string(content)
+
+		bevat := append([]string{"<h1>Indeling</h1>", "Seed 42", "stopped: interrupted at generation 7",
+			fmt.Sprintf("Cost %.0f", X.Evaluate())}, test.bevat...)
+
+		//a row per groep and every team
+		for _, groep := range []string{"M", "1A", "1B"} {
+			bevat = append(bevat, `<td class="tekst">`+groep+`</td>`)
+		}
+		for _, tid := range X {
+			bevat = append(bevat, optimizer.matrix.GetTeamInfoByCostID(tid).team.id)
+		}
+
+		for _, s := range bevat {
+			if !strings.Contains(report, s) {
+				t.Errorf("%v: report without %q", test.naam, s)
+			}
+		}
+
+		for _, s := range test.ontbreekt {
+			if strings.Contains(report, s) {
+				t.Errorf("%v: report with %q", test.naam, s)
+			}
+		}
+	}
+}
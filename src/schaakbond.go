@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
 
 //Verplaatsing voor spelen Uit/Thuis
 type Verplaatsing byte
@@ -37,6 +41,9 @@ type Ronde struct {
 	Wedstrijden [5]Wedstrijd
 }
 
+//GroepGrootte is the number of teams in a groep
+const GroepGrootte = 10
+
 //SpeelSchema voor schaken
 type SpeelSchema struct {
 	Loten  [10]Lot
@@ -57,6 +64,20 @@ const (
 	Derde
 )
 
+func (x Klasse) String() string {
+	switch x {
+	case Meester:
+		return "M"
+	case Eerste:
+		return "1"
+	case Tweede:
+		return "2"
+	case Derde:
+		return "3"
+	}
+	return fmt.Sprintf("Klasse(%d)", byte(x))
+}
+
 //Gradatie wijziging
 type Gradatie byte
 
@@ -71,31 +92,252 @@ const (
 	Kampioen
 )
 
+func (x Gradatie) String() string {
+	switch x {
+	case Ongewijzigd:
+		return "Ongewijzigd"
+	case Promotie:
+		return "Promotie"
+	case Degradatie:
+		return "Degradatie"
+	case Kampioen:
+		return "Kampioen"
+	}
+	return fmt.Sprintf("Gradatie(%d)", byte(x))
+}
+
+//Code of the gradatie in the P/D column
+func (x Gradatie) Code() string {
+	switch x {
+	case Promotie:
+		return "P"
+	case Degradatie:
+		return "D"
+	case Kampioen:
+		return "K"
+	}
+	return ""
+}
+
 //Team van een vereniging
 type Team struct {
 	id, naam   string
 	klasse     Klasse
 	pd         Gradatie
 	vereniging Vereniging
+	//groep en lot van het huidige seizoen, leeg voor nieuwe teams
+	groep string
+	lot   LotNummer
 }
 
 func (x Team) String() string {
-	return fmt.Sprintf("{ id: %s, naam: %s, klasse: %v, pd: %v, vereniging: %s}", x.id, x.naam, x.klasse, x.pd, x.vereniging.id)
+	return fmt.Sprintf("{ id: %s, naam: %s, klasse: %v, pd: %v, vereniging: %s, groep: %s}", x.id, x.naam, x.klasse, x.pd, x.vereniging.id, x.groep)
+}
+
+//Bond (regionale schaakbond) van een vereniging
+type Bond struct {
+	id, naam string
+}
+
+func (x Bond) String() string {
+	return fmt.Sprintf("{ id: %s, naam: %s}", x.id, x.naam)
+}
+
+//bondNamen by the first two digits of a vereniging id. The Indeling sheet has
+//no bond column, so a vereniging with a new or renumbered id gets the id as
+//bond naam unless its new team names the bond in the groep column, or the
+//teams file has a bond column
+var bondNamen = map[string]string{
+	"01": "FSB",
+	"02": "NOSBO",
+	"03": "SBO",
+	"04": "OSBO",
+	"06": "SGS",
+	"08": "SGA",
+	"09": "NHSB",
+	"11": "LeiSB",
+	"12": "HSB",
+	"14": "RSB",
+	"16": "ZSB",
+	"17": "NBSB",
+	"19": "LiSB",
 }
 
 //Vereniging van de Schaakbond
 type Vereniging struct {
 	id, naam, plaats string
+	bond             *Bond
 	teams            map[string]Team
 }
 
 func (x Vereniging) String() string {
-	return fmt.Sprintf("{ id: %s, naam: %s, plaats: %s, teams: %d}", x.id, x.naam, x.plaats, len(x.teams))
+	return fmt.Sprintf("{ id: %s, naam: %s, plaats: %s, bond: %s, teams: %d}", x.id, x.naam, x.plaats, x.bond.naam, len(x.teams))
+}
+
+//WensSoort of a wens of a team
+type WensSoort byte
+
+const (
+	//Samen in dezelfde groep spelen
+	Samen WensSoort = iota
+	//NietSamen in dezelfde groep spelen
+	NietSamen
+)
+
+//Wens of a team about the groep of another team
+type Wens struct {
+	soort               WensSoort
+	teamID, anderTeamID string
+	//row in the sheet, for reporting
+	row int
+}
+
+func (x Wens) String() string {
+	if x.soort == NietSamen {
+		return fmt.Sprintf("%s niet samen met %s", x.teamID, x.anderTeamID)
+	}
+	return fmt.Sprintf("%s samen met %s", x.teamID, x.anderTeamID)
+}
+
+//Regel of the Indeling sheet as it was loaded, kept for validation
+type Regel struct {
+	row                          int
+	teamID, verenigingID, plaats string
 }
 
 //Schaakbond van Nederland
 type Schaakbond struct {
+	bonden       map[string]*Bond
 	verenigingen map[string]Vereniging
 	teams        map[string]Team
 	klasses      map[Klasse][]Team
+	wensen       []Wens
+	regels       []Regel
+	//sheet or file the teams are loaded from, for the messages of Validate
+	sheet string
+	//fouten in the rows of the sheet, with the cell, reported by Validate
+	fouten []string
+	//seizoen of the groep column, e.g. 16/17, empty if unknown
+	seizoen string
+}
+
+var seizoenRegexp = regexp.MustCompile(`[0-9]{2}/[0-9]{2}`)
+
+//parseSeizoen in a header like kl16/17
+func parseSeizoen(header string) string {
+	return seizoenRegexp.FindString(header)
+}
+
+//volgendSeizoen of a seizoen, 16/17 is followed by 17/18
+func volgendSeizoen(seizoen string) string {
+	var van, tot int
+
+	if _, err := fmt.Sscanf(seizoen, "%d/%d", &van, &tot); err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%02d/%02d", (van+1)%100, (tot+1)%100)
+}
+
+var teamNummerRegexp = regexp.MustCompile(`\s([0-9]{1,2})$`)
+
+//teamNummer in a team naam like Caissa 2, the first team often has none.
+//Longer numbers are part of the naam, as in Philidor 1847
+func teamNummer(naam string) string {
+	if match := teamNummerRegexp.FindStringSubmatch(strings.TrimSpace(naam)); match != nil {
+		return match[1]
+	}
+	return "1"
+}
+
+//FindTeam of a vereniging by its naam. The sheets spell the naam of a team
+//differently, e.g. Zukertort Amstelveen 1 and Zukertort Amstelveen, so
+//without an exact match the team is found by its number: the team id is the
+//vereniging id followed by the number
+func (sb *Schaakbond) FindTeam(verenigingID string, naam string) (Team, bool) {
+	v, ok := sb.verenigingen[verenigingID]
+
+	if !ok {
+		return Team{}, false
+	}
+
+	for _, t := range v.teams {
+		if t.naam == naam {
+			return t, true
+		}
+	}
+
+	t, ok := v.teams[verenigingID+teamNummer(naam)]
+	return t, ok
+}
+
+//GetOrAddBond of a vereniging id, the bond is identified by the first two
+//digits of the id
+func (sb *Schaakbond) GetOrAddBond(verenigingID string) *Bond {
+	id := verenigingID
+	if len(id) > 2 {
+		id = id[:2]
+	}
+
+	b := sb.bonden[id]
+
+	if b != nil {
+		return b
+	}
+
+	b = new(Bond)
+	b.id = id
+	b.naam = bondNamen[id]
+
+	if b.naam == "" {
+		b.naam = id
+	}
+
+	sb.bonden[id] = b
+	return b
+}
+
+//GetOrAddNamedBond by its naam as given in the teams sheet, a bond of
+//bondNamen keeps its id
+func (sb *Schaakbond) GetOrAddNamedBond(naam string) *Bond {
+	for _, b := range sb.bonden {
+		if b.naam == naam {
+			return b
+		}
+	}
+
+	for id, bondNaam := range bondNamen {
+		if bondNaam == naam {
+			return sb.GetOrAddBond(id)
+		}
+	}
+
+	b := new(Bond)
+	b.id = naam
+	b.naam = naam
+	sb.bonden[naam] = b
+	return b
+}
+
+//HuidigeIndeling of the teams, teams without groep are left out
+func (sb *Schaakbond) HuidigeIndeling() *Indeling {
+	indeling := new(Indeling)
+	indeling.plaatsingen = make(map[string]Plaatsing)
+
+	for _, t := range sb.teams {
+		if t.groep == "" {
+			continue
+		}
+
+		klasse, _ := parseKlasse(t.groep)
+
+		var p Plaatsing
+		p.teamID = t.id
+		p.groep = t.groep
+		p.klasse = klasse
+		p.lot = t.lot
+		indeling.plaatsingen[t.id] = p
+	}
+
+	return indeling
 }
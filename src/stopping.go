@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+//StoppingCriteria of an optimization run, a zero value disables a criterium
+type StoppingCriteria struct {
+	MaxGenerations int
+	MaxDuration    time.Duration
+	MaxStagnation  int
+	TargetCost     float64
+}
+
+//Stopper decides when an optimization run is finished
+type Stopper struct {
+	criteria        StoppingCriteria
+	started         time.Time
+	bestFitness     float64
+	lastImprovement int
+	evaluated       bool
+}
+
+//NewStopper for the criteria, starting the clock now
+func NewStopper(criteria StoppingCriteria) *Stopper {
+	stopper := new(Stopper)
+	stopper.criteria = criteria
+	stopper.started = time.Now()
+	return stopper
+}
+
+//Check the criteria after a generation, returns the reason to stop or an
+//empty string to continue
+func (stopper *Stopper) Check(generation int, fitness float64) string {
+	if !stopper.evaluated || fitness < stopper.bestFitness {
+		stopper.bestFitness = fitness
+		stopper.lastImprovement = generation
+		stopper.evaluated = true
+	}
+
+	criteria := stopper.criteria
+
+	if criteria.TargetCost > 0 && fitness <= criteria.TargetCost {
+		return fmt.Sprintf("target cost %f reached", criteria.TargetCost)
+	}
+
+	if criteria.MaxStagnation > 0 && generation-stopper.lastImprovement >= criteria.MaxStagnation {
+		return fmt.Sprintf("no improvement for %d generations", criteria.MaxStagnation)
+	}
+
+	if criteria.MaxDuration > 0 && time.Since(stopper.started) >= criteria.MaxDuration {
+		return fmt.Sprintf("time budget of %v used", criteria.MaxDuration)
+	}
+
+	if criteria.MaxGenerations > 0 && generation >= criteria.MaxGenerations {
+		return fmt.Sprintf("maximum of %d generations reached", criteria.MaxGenerations)
+	}
+
+	return ""
+}
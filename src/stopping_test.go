@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopperCheck(t *testing.T) {
+	//generation and fitness per check, the reason of the last one
+	type check struct {
+		generation int
+		fitness    float64
+	}
+
+	tests := []struct {
+		naam     string
+		criteria StoppingCriteria
+		started  time.Duration
+		checks   []check
+		reden    string
+	}{
+		{"no criteria", StoppingCriteria{}, 0, []check{{1, 10}, {1000000, 10}}, ""},
+		{"below max generations", StoppingCriteria{MaxGenerations: 10}, 0, []check{{9, 10}}, ""},
+		{"max generations", StoppingCriteria{MaxGenerations: 10}, 0, []check{{10, 10}}, "maximum of 10 generations"},
+		{"target not reached", StoppingCriteria{TargetCost: 5}, 0, []check{{1, 5.5}}, ""},
+		{"target reached", StoppingCriteria{TargetCost: 5}, 0, []check{{1, 5}}, "target cost"},
+		{"improving", StoppingCriteria{MaxStagnation: 3}, 0, []check{{1, 10}, {2, 9}, {3, 8}, {4, 7}}, ""},
+		{"stagnating", StoppingCriteria{MaxStagnation: 3}, 0, []check{{1, 10}, {2, 9}, {3, 9}, {4, 9}, {5, 9}}, "no improvement for 3"},
+		{"worse is no improvement", StoppingCriteria{MaxStagnation: 2}, 0, []check{{1, 10}, {2, 11}, {3, 12}}, "no improvement for 2"},
+		{"within time", StoppingCriteria{MaxDuration: time.Hour}, 0, []check{{1, 10}}, ""},
+		{"time used", StoppingCriteria{MaxDuration: time.Hour}, 2 * time.Hour, []check{{1, 10}}, "time budget"},
+		{"target before generations", StoppingCriteria{MaxGenerations: 10, TargetCost: 5}, 0, []check{{10, 4}}, "target cost"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			stopper := NewStopper(test.criteria)
+			stopper.started = stopper.started.Add(-test.started)
+
+			reden := ""
+			for _, c := range test.checks {
+				reden = stopper.Check(c.generation, c.fitness)
+			}
+
+			if test.reden == "" && reden != "" {
+				t.Errorf("stopped: %v", reden)
+			}
+
+			if test.reden != "" && !strings.HasPrefix(reden, test.reden) {
+				t.Errorf("reason %q, expected %q", reden, test.reden)
+			}
+		})
+	}
+}
+
+func TestStopReasonInResults(t *testing.T) {
+	newTestOptimizer(t)
+	X := testVector(t)
+
+	stopReason = "no improvement for 10 generations at generation 42"
+	defer func() { stopReason = "" }()
+
+	if breakdown := X.Explain(); breakdown.StopReason != stopReason {
+		t.Errorf("Stop reason %q in the breakdown", breakdown.StopReason)
+	}
+
+	if report := X.NewReport(); report.StopReason != stopReason {
+		t.Errorf("Stop reason %q in the report", report.StopReason)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//verwachteGradatie of a team moving from a klasse to the next klasse
+func verwachteGradatie(van Klasse, naar Klasse, kampioen bool) Gradatie {
+	switch {
+	case naar < van:
+		return Promotie
+	case naar > van:
+		return Degradatie
+	case kampioen && naar == Meester:
+		return Kampioen
+	}
+	return Ongewijzigd
+}
+
+//CheckGradaties of the teams against the promovendi and eindstanden sheets,
+//returns a description of every inconsistency
+func CheckGradaties(sb *Schaakbond, promovendi []Promovendus, eindstanden []Eindstand) []string {
+	problems := make([]string, 0)
+	verklaard := make(map[string]bool)
+
+	for _, p := range promovendi {
+		t, ok := sb.FindTeam(p.verenigingID, p.naam)
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("promovendi row %d: team %v (%v) not found in %v", p.row, p.naam, p.verenigingID, sb.sheet))
+			continue
+		}
+
+		verklaard[t.id] = true
+
+		if t.klasse != Derde {
+			problems = append(problems, fmt.Sprintf("promovendi row %d: team %v is in klasse %v instead of %v", p.row, t.id, t.klasse, Derde))
+		}
+
+		if t.pd != Promotie {
+			problems = append(problems, fmt.Sprintf("promovendi row %d: team %v has P/D %v instead of %v", p.row, t.id, t.pd, Promotie))
+		}
+
+		if bond := strings.Fields(p.bond); len(bond) > 0 && bond[0] != t.vereniging.bond.naam {
+			problems = append(problems, fmt.Sprintf("promovendi row %d: team %v promotes from %v but its vereniging is in bond %v", p.row, t.id, p.bond, t.vereniging.bond.naam))
+		}
+	}
+
+	for _, e := range eindstanden {
+		t, ok := sb.FindTeam(e.verenigingID, e.naam)
+
+		if !ok {
+			if e.inVolgendeKlasse {
+				problems = append(problems, fmt.Sprintf("eindstanden row %d: team %v (%v) not found in %v", e.row, e.naam, e.verenigingID, sb.sheet))
+			}
+			continue
+		}
+
+		verklaard[t.id] = true
+
+		if !e.inVolgendeKlasse {
+			problems = append(problems, fmt.Sprintf("eindstanden row %d: team %v leaves the competition but is in %v", e.row, t.id, sb.sheet))
+			continue
+		}
+
+		if t.klasse != e.volgendeKlasse {
+			problems = append(problems, fmt.Sprintf("eindstanden row %d: team %v is in klasse %v instead of %v", e.row, t.id, t.klasse, e.volgendeKlasse))
+		}
+
+		if t.groep != "" && t.groep != e.groep {
+			problems = append(problems, fmt.Sprintf("eindstanden row %d: team %v played in groep %v instead of %v", e.row, t.id, t.groep, e.groep))
+		}
+
+		huidigeKlasse, _ := parseKlasse(e.groep)
+		pd := verwachteGradatie(huidigeKlasse, e.volgendeKlasse, e.kampioen)
+
+		if t.pd != pd {
+			problems = append(problems, fmt.Sprintf("eindstanden row %d: team %v has P/D %v instead of %v", e.row, t.id, t.pd, pd))
+		}
+	}
+
+	teamIDs := make([]string, 0, len(sb.teams))
+	for id := range sb.teams {
+		teamIDs = append(teamIDs, id)
+	}
+	sort.Strings(teamIDs)
+
+	for _, id := range teamIDs {
+		t := sb.teams[id]
+		if t.pd != Ongewijzigd && !verklaard[t.id] {
+			problems = append(problems, fmt.Sprintf("%v: team %v has P/D %v but is not in promovendi or eindstanden", sb.sheet, t.id, t.pd))
+		}
+	}
+
+	return problems
+}
+
+//CheckWensen of unknown teams, returns the usable wensen and a description of
+//every wens left out
+func CheckWensen(sb *Schaakbond, wensen []Wens) ([]Wens, []string) {
+	valid := make([]Wens, 0, len(wensen))
+	problems := make([]string, 0)
+
+	for _, w := range wensen {
+		_, a := sb.teams[w.teamID]
+		_, b := sb.teams[w.anderTeamID]
+
+		switch {
+		case !a:
+			problems = append(problems, fmt.Sprintf("wensen row %d: unknown team %v", w.row, w.teamID))
+		case !b:
+			problems = append(problems, fmt.Sprintf("wensen row %d: unknown team %v", w.row, w.anderTeamID))
+		case w.teamID == w.anderTeamID:
+			problems = append(problems, fmt.Sprintf("wensen row %d: team %v has a wens about itself", w.row, w.teamID))
+		default:
+			valid = append(valid, w)
+		}
+	}
+
+	return valid, problems
+}
+
+//Validate the teams before optimizing, including the fouten found while
+//loading them. Fouten make optimizing impossible, waarschuwingen are logged:
+//a vereniging in two plaatsen plays in the plaats of its first row, a P/D
+//which can't be divided makes every indeling pay a penalty
+func Validate(sb *Schaakbond) ([]string, []string) {
+	fouten := append(make([]string, 0), sb.fouten...)
+	waarschuwingen := make([]string, 0)
+
+	teamRows := make(map[string]int)
+	verenigingRegels := make(map[string]Regel)
+
+	for _, r := range sb.regels {
+		if first, ok := teamRows[r.teamID]; ok {
+			fouten = append(fouten, fmt.Sprintf("%v row %d: duplicate team %v, first in row %d", sb.sheet, r.row, r.teamID, first))
+		} else {
+			teamRows[r.teamID] = r.row
+		}
+
+		if first, ok := verenigingRegels[r.verenigingID]; ok {
+			if first.plaats != r.plaats {
+				waarschuwingen = append(waarschuwingen, fmt.Sprintf("%v row %d: vereniging %v in plaats %v, but in row %d in %v, which is used", sb.sheet, r.row, r.verenigingID, r.plaats, first.row, first.plaats))
+			}
+		} else {
+			verenigingRegels[r.verenigingID] = r
+		}
+	}
+
+	for k := Meester; k <= Derde; k++ {
+		teams := sb.klasses[k]
+
+		if len(teams)%GroepGrootte != 0 {
+			fouten = append(fouten, fmt.Sprintf("Klasse %v has %d teams, not a multiple of %d", k, len(teams), GroepGrootte))
+		}
+
+		var promovendi, degradanten int
+		for _, t := range teams {
+			switch t.pd {
+			case Promotie, Kampioen:
+				promovendi++
+			case Degradatie:
+				degradanten++
+			}
+		}
+
+		groepen := len(teams) / GroepGrootte
+		if promovendi != 2*groepen || degradanten != groepen {
+			waarschuwingen = append(waarschuwingen, fmt.Sprintf("Klasse %v: %d promovendi/kampioenen and %d degradanten can't be divided over %d groepen with 2 and 1 each", k, promovendi, degradanten, groepen))
+		}
+	}
+
+	return fouten, waarschuwingen
+}
+
+//ValidateAfstanden of the plaatsen of the verenigingen in the distance
+//matrix, every pair of plaatsen in a klasse may play each other. Run against
+//the cached distances it lists what GetTravelInformation has to request
+func ValidateAfstanden(sb *Schaakbond, distanceMatrix *DistanceMatrix) []string {
+	fouten := make([]string, 0)
+
+	verenigingRows := make(map[string]int)
+	for _, r := range sb.regels {
+		if _, ok := verenigingRows[r.verenigingID]; !ok {
+			verenigingRows[r.verenigingID] = r.row
+		}
+	}
+
+	verenigingIDs := make([]string, 0, len(sb.verenigingen))
+	for id := range sb.verenigingen {
+		verenigingIDs = append(verenigingIDs, id)
+	}
+	sort.Strings(verenigingIDs)
+
+	for _, id := range verenigingIDs {
+		v := sb.verenigingen[id]
+
+		if distanceMatrix.GetCityByName(CityName(v.plaats)) == nil {
+			fouten = append(fouten, fmt.Sprintf("%v row %d: plaats %v of vereniging %v not in the distance matrix", sb.sheet, verenigingRows[id], v.plaats, id))
+		}
+	}
+
+	for k := Meester; k <= Derde; k++ {
+		plaatsen := make(map[string]bool)
+		for _, t := range sb.klasses[k] {
+			plaatsen[t.vereniging.plaats] = true
+		}
+
+		namen := make([]string, 0, len(plaatsen))
+		for plaats := range plaatsen {
+			namen = append(namen, plaats)
+		}
+		sort.Strings(namen)
+
+		for a := 0; a < len(namen); a++ {
+			for b := a + 1; b < len(namen); b++ {
+				from := distanceMatrix.GetCityByName(CityName(namen[a]))
+				to := distanceMatrix.GetCityByName(CityName(namen[b]))
+
+				if from != nil && to != nil && distanceMatrix.GetTravelInformation(from.ID, to.ID) == nil {
+					fouten = append(fouten, fmt.Sprintf("Klasse %v: no travel information between %v and %v", k, namen[a], namen[b]))
+				}
+			}
+		}
+	}
+
+	return fouten
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	regel := func(record ...string) []string { return record }
+	header := regel("Teamid", "zoek", "Teams", "Id", "Plaats", "P/D")
+
+	tests := []struct {
+		naam    string
+		records [][]string
+		//fouten expected in this order, at the start of the fouten
+		fouten []string
+		//waarschuwingen expected in this order, at the start
+		waarschuwingen []string
+	}{
+		{"test teams", testRecords(), nil, nil},
+		{"every wrong row",
+			append(testRecords(),
+				regel("0100161", "4", "V16 1", "010016", "Plaats1", ""),
+				regel("0100171", "1", "V17 1", "", "Plaats1", ""),
+				regel("0100011", "1", "V1 1", "010001", "Plaats1", "")),
+			[]string{"test!B32: Unknown Klasse value of team 0100161", "test!D33: missing Vereniging Id of team 0100171", "test row 34: duplicate team 0100011"}, nil},
+		{"vereniging in two plaatsen",
+			[][]string{header, regel("0100011", "1", "V1 1", "010001", "Plaats1", ""), regel("0100012", "1", "V1 2", "010001", "Plaats2", "")},
+			[]string{"Klasse 1 has 2 teams"}, []string{"test row 3: vereniging 010001 in plaats Plaats2, but in row 2 in Plaats1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.naam, func(t *testing.T) {
+			sb, err := loadSchaakbondSheet(sheetOfRecords("test", test.records), false)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fouten, waarschuwingen := Validate(sb)
+
+			if len(fouten) < len(test.fouten) || (test.fouten == nil && len(fouten) > 0) {
+				t.Fatalf("fouten %v, expected %v", fouten, test.fouten)
+			}
+
+			for ix, fout := range test.fouten {
+				if !strings.HasPrefix(fouten[ix], fout) {
+					t.Errorf("fout %q, expected %q", fouten[ix], fout)
+				}
+			}
+
+			if len(waarschuwingen) < len(test.waarschuwingen) {
+				t.Fatalf("waarschuwingen %v, expected %v", waarschuwingen, test.waarschuwingen)
+			}
+
+			for ix, waarschuwing := range test.waarschuwingen {
+				if !strings.HasPrefix(waarschuwingen[ix], waarschuwing) {
+					t.Errorf("waarschuwing %q, expected %q", waarschuwingen[ix], waarschuwing)
+				}
+			}
+
+			//the plaats of the first row is used
+			if v, ok := sb.verenigingen["010001"]; ok && v.plaats != "Plaats1" {
+				t.Errorf("Vereniging 010001 in %v", v.plaats)
+			}
+		})
+	}
+}
+
+func TestValidateAfstanden(t *testing.T) {
+	sb, err := loadSchaakbondSheet(sheetOfRecords("test", testRecords()), false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var zonderPlaats5 []TravelInformation
+	for _, info := range testDistances() {
+		if info.City[1] != CityName("Plaats5") {
+			zonderPlaats5 = append(zonderPlaats5, info)
+		}
+	}
+
+	tests := []struct {
+		naam   string
+		info   []TravelInformation
+		fouten int
+	}{
+		{"all distances", testDistances(), 0},
+		{"empty cache", nil, 15},
+		//Plaats1 - Plaats2 is missing in klasse M and 1
+		{"missing pair", testDistances()[1:], 2},
+		//Plaats5 is missing for verenigingen 5, 10 and 15
+		{"missing plaats", zonderPlaats5, 3},
+	}
+
+	for _, test := range tests {
+		fouten := ValidateAfstanden(sb, CreateDistanceMatrixWithTravelInformations(test.info))
+
+		if len(fouten) != test.fouten {
+			t.Errorf("%v: fouten %v, expected %d", test.naam, fouten, test.fouten)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+)
+
+//Verhinderingen of teams which can't play thuis in some rondes, e.g. because
+//their hall is rented out. Every ronde a team plays thuis anyway multiplies
+//the cost by the penalty
+type Verhinderingen struct {
+	//Teams rondes by team id
+	Teams map[string][]int `json:"teams"`
+	//Verenigingen rondes by vereniging id, for all its teams
+	Verenigingen map[string][]int `json:"verenigingen"`
+
+	rondes  [256][]int
+	penalty float64
+}
+
+//VerhinderdConflict of a team playing thuis in a ronde it can't
+type VerhinderdConflict struct {
+	Team  string `json:"team"`
+	Groep string `json:"groep"`
+	Lot   int    `json:"lot"`
+	Ronde int    `json:"ronde"`
+}
+
+//LoadVerhinderingen from a json file, rondes count from 1
+func (optimizer *Optimizer) LoadVerhinderingen(fileName string, penalty float64) (*Verhinderingen, error) {
+	file, err := ioutil.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	verhinderingen := new(Verhinderingen)
+	err = json.Unmarshal(file, verhinderingen)
+
+	if err != nil {
+		return nil, err
+	}
+
+	verhinderingen.penalty = penalty
+	rondes := make(map[TeamCostID]map[int]bool)
+
+	add := func(teamID string, ronde int) error {
+		if ronde < 1 || ronde > len(optimizer.schema.Rondes) {
+			return fmt.Errorf("Ronde %d of team %v in %v is not between 1 and %d", ronde, teamID, fileName, len(optimizer.schema.Rondes))
+		}
+
+		tid := optimizer.matrix.GetTeamCostID(teamID)
+		if rondes[tid] == nil {
+			rondes[tid] = make(map[int]bool)
+		}
+		rondes[tid][ronde-1] = true
+		return nil
+	}
+
+	for teamID, teamRondes := range verhinderingen.Teams {
+		if _, ok := optimizer.bond.teams[teamID]; !ok {
+			return nil, fmt.Errorf("Unknown team %v in %v", teamID, fileName)
+		}
+
+		for _, ronde := range teamRondes {
+			if err = add(teamID, ronde); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for verenigingID, verenigingRondes := range verhinderingen.Verenigingen {
+		v, ok := optimizer.bond.verenigingen[verenigingID]
+
+		if !ok {
+			return nil, fmt.Errorf("Unknown vereniging %v in %v", verenigingID, fileName)
+		}
+
+		for teamID := range v.teams {
+			for _, ronde := range verenigingRondes {
+				if err = add(teamID, ronde); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for tid, set := range rondes {
+		for ronde := range set {
+			verhinderingen.rondes[tid] = append(verhinderingen.rondes[tid], ronde)
+		}
+		sort.Ints(verhinderingen.rondes[tid])
+	}
+
+	return verhinderingen, nil
+}
+
+//Count of the teams with rondes they can't play thuis
+func (verhinderingen *Verhinderingen) Count() int {
+	count := 0
+	for _, rondes := range verhinderingen.rondes {
+		if len(rondes) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+//conflicts of the vector, calling found for every one
+func (verhinderingen *Verhinderingen) conflicts(X Vector, found func(ix int, ronde int)) {
+	for ix, tid := range X {
+		description := optimizer.descriptions[ix]
+
+		if description == nil {
+			continue
+		}
+
+		for _, ronde := range verhinderingen.rondes[tid] {
+			if optimizer.schema.Loten[ix-description.begin].Rondes[ronde].Verplaatsing == Thuis {
+				found(ix, ronde)
+			}
+		}
+	}
+}
+
+//multiplier of the cost of the vector, the penalty for every ronde a team
+//plays thuis while it can't
+func (verhinderingen *Verhinderingen) multiplier(X Vector) float64 {
+	count := 0
+	verhinderingen.conflicts(X, func(ix int, ronde int) { count++ })
+	return math.Pow(verhinderingen.penalty, float64(count))
+}
+
+//Conflicten of the vector
+func (verhinderingen *Verhinderingen) Conflicten(X Vector) []VerhinderdConflict {
+	var conflicten []VerhinderdConflict
+
+	verhinderingen.conflicts(X, func(ix int, ronde int) {
+		description := optimizer.descriptions[ix]
+		conflicten = append(conflicten, VerhinderdConflict{
+			optimizer.matrix.GetTeamInfoByCostID(X[ix]).team.id,
+			description.Naam(),
+			ix - description.begin + 1,
+			ronde + 1,
+		})
+	})
+
+	return conflicten
+}
+
+//String of a conflict
+func (conflict VerhinderdConflict) String() string {
+	return fmt.Sprintf("team %s lot %d of groep %s plays thuis in ronde %d", conflict.Team, conflict.Lot, conflict.Groep, conflict.Ronde)
+}
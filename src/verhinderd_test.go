@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestVerhinderingen(t *testing.T) {
+	tests := []struct {
+		naam    string
+		content string
+		//teams with verhinderingen
+		teams int
+		//rondes of the team on lot 1 of M and of the team on lot 1 of 1A
+		rondesM, rondes1 []int
+		fout             string
+	}{
+		{"team", `{ "teams": { "0100011": [1, 2, 3] } }`, 1, []int{1, 2, 3}, nil, ""},
+		{"vereniging", `{ "verenigingen": { "010001": [4, 9] } }`, 2, []int{4, 9}, []int{4, 9}, ""},
+		{"team and vereniging", `{ "teams": { "0100011": [1, 4] }, "verenigingen": { "010001": [4, 9] } }`, 2, []int{1, 4, 9}, []int{4, 9}, ""},
+		{"every ronde", `{ "teams": { "0100012": [1, 2, 3, 4, 5, 6, 7, 8, 9] } }`, 1, nil, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, ""},
+		{"ronde out of range", `{ "teams": { "0100011": [10] } }`, 0, nil, nil, "Ronde 10 of team 0100011"},
+		{"unknown team", `{ "teams": { "0100999": [1] } }`, 0, nil, nil, "Unknown team 0100999"},
+		{"unknown vereniging", `{ "verenigingen": { "019999": [1] } }`, 0, nil, nil, "Unknown vereniging 019999"},
+	}
+
+	for _, test := range tests {
+		newTestOptimizer(t)
+		//0100011 on lot 1 of M and 0100012 on lot 1 of 1A
+		X := testVector(t)
+
+		fileName, remove := writeTestFile(t, "verhinderd.json", test.content)
+		verhinderingen, err := optimizer.LoadVerhinderingen(fileName, 2)
+		remove()
+
+		if test.fout != "" {
+			if err == nil || !strings.Contains(err.Error(), test.fout) {
+				t.Errorf("%v: error %v, expected %v", test.naam, err, test.fout)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%v: %v", test.naam, err)
+		}
+
+		if c := verhinderingen.Count(); c != test.teams {
+			t.Errorf("%v: %d teams, expected %d", test.naam, c, test.teams)
+		}
+
+		count := 0
+		for _, ronde := range append(test.rondesM, test.rondes1...) {
+			if thuisRondes(1)[ronde] {
+				count++
+			}
+		}
+
+		if m := verhinderingen.multiplier(X); m != math.Pow(2, float64(count)) {
+			t.Errorf("%v: multiplier %v, expected 2^%d", test.naam, m, count)
+		}
+
+		conflicten := verhinderingen.Conflicten(X)
+
+		if len(conflicten) != count {
+			t.Errorf("%v: conflicten %v, expected %d", test.naam, conflicten, count)
+		}
+
+		for _, c := range conflicten {
+			if c.Lot != 1 || !thuisRondes(1)[c.Ronde] {
+				t.Errorf("%v: conflict %v", test.naam, c)
+			}
+		}
+	}
+}